@@ -5,8 +5,11 @@ import (
 	"io"
 )
 
-// Interface to integrate with Templ components
-type templComponent interface {
+// TemplComponent is the interface to integrate with Templ components.
+//
+// Components generated by Templ satisfy this interface, so it can be used
+// in the signatures of your own functions that call [htmx.Response.RenderTempl].
+type TemplComponent interface {
 	// Render the template.
 	Render(ctx context.Context, w io.Writer) error
 }
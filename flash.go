@@ -0,0 +1,103 @@
+package htmx
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// FlashCookieName is the name of the cookie used by [htmx.Response.RedirectWithFlash].
+var FlashCookieName = "htmx-flash"
+
+// Flash is a one-time message stored by [htmx.Response.RedirectWithFlash]
+// and read on the next request with [htmx.GetFlash].
+type Flash struct {
+	// The name of the event for the message.
+	Event string `json:"event"`
+	// The JSON-encoded detail of the message.
+	Detail json.RawMessage `json:"detail,omitempty"`
+}
+
+// RedirectWithFlash does a client-side redirect to a new location, storing a one-time
+// flash message in a cookie for the redirected page to display.
+//
+// 'HX-Redirect' makes the browser load a new page, so triggers on this response
+// would be lost. Instead, the handler of the redirected page reads the message
+// with [htmx.GetFlash], renders it, and removes it with [htmx.Response.ClearFlash].
+//
+// The detail **must** be serializable to JSON; if it isn't, the error
+// is returned by [htmx.Response.Write].
+//
+// Sets the 'HX-Redirect' header and the cookie named by [htmx.FlashCookieName].
+func (r Response) RedirectWithFlash(path string, event string, detail any) Response {
+	r = r.Redirect(path)
+
+	flash, err := newFlash(event, detail)
+	if err != nil {
+		r.errs = append(r.errs[:len(r.errs):len(r.errs)], fmt.Errorf("marshalling flash failed: %w", err))
+		return r
+	}
+
+	bytes, err := json.Marshal(flash)
+	if err != nil {
+		r.errs = append(r.errs[:len(r.errs):len(r.errs)], fmt.Errorf("marshalling flash failed: %w", err))
+		return r
+	}
+
+	return r.Cookie(&http.Cookie{
+		Name:     FlashCookieName,
+		Value:    base64.RawURLEncoding.EncodeToString(bytes),
+		Path:     "/",
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// ClearFlash removes the flash message stored by [htmx.Response.RedirectWithFlash],
+// so it is only displayed once.
+//
+// Expires the cookie named by [htmx.FlashCookieName].
+func (r Response) ClearFlash() Response {
+	return r.Cookie(&http.Cookie{
+		Name:   FlashCookieName,
+		Value:  "",
+		Path:   "/",
+		MaxAge: -1,
+	})
+}
+
+// GetFlash returns the flash message stored by [htmx.Response.RedirectWithFlash].
+//
+// Returns false if there is no flash cookie or it is malformed.
+func GetFlash(r *http.Request) (Flash, bool) {
+	c, err := r.Cookie(FlashCookieName)
+	if err != nil {
+		return Flash{}, false
+	}
+
+	bytes, err := base64.RawURLEncoding.DecodeString(c.Value)
+	if err != nil {
+		return Flash{}, false
+	}
+
+	var flash Flash
+	if err := json.Unmarshal(bytes, &flash); err != nil {
+		return Flash{}, false
+	}
+
+	return flash, true
+}
+
+func newFlash(event string, detail any) (Flash, error) {
+	if detail == nil {
+		return Flash{Event: event}, nil
+	}
+
+	bytes, err := json.Marshal(detail)
+	if err != nil {
+		return Flash{}, err
+	}
+
+	return Flash{Event: event, Detail: bytes}, nil
+}
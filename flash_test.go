@@ -0,0 +1,61 @@
+package htmx
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestRedirectWithFlash(t *testing.T) {
+	w := newMockResponseWriter()
+
+	err := NewResponse().
+		RedirectWithFlash("/contacts", "showMessage", map[string]string{"message": "Contact saved"}).
+		Write(w)
+	if err != nil {
+		t.Errorf("an error occurred writing a response: %v", err)
+	}
+
+	if got := w.header.Get(HeaderRedirect); got != "/contacts" {
+		t.Errorf("wrong value for header %q. got=%q, want=%q", HeaderRedirect, got, "/contacts")
+	}
+
+	// Send the cookie back on the next request
+	next := &http.Request{Header: http.Header{}}
+	for _, c := range w.header.Values("Set-Cookie") {
+		next.Header.Add("Cookie", c)
+	}
+
+	flash, ok := GetFlash(next)
+	if !ok {
+		t.Fatalf("expected a flash message")
+	}
+
+	if flash.Event != "showMessage" {
+		t.Errorf("wrong flash event. got=%q, want=%q", flash.Event, "showMessage")
+	}
+	if want := `{"message":"Contact saved"}`; string(flash.Detail) != want {
+		t.Errorf("wrong flash detail. got=%q, want=%q", string(flash.Detail), want)
+	}
+
+	w = newMockResponseWriter()
+	NewResponse().ClearFlash().MustWrite(w)
+	if got := w.header.Get("Set-Cookie"); got != "htmx-flash=; Path=/; Max-Age=0" {
+		t.Errorf("wrong clearing cookie. got=%q", got)
+	}
+
+	if _, ok := GetFlash(&http.Request{Header: http.Header{}}); ok {
+		t.Errorf("expected no flash message without a cookie")
+	}
+
+	if err := NewResponse().RedirectWithFlash("/", "bad", make(chan int)).Err(); err == nil {
+		t.Errorf("expected an error for an unserializable detail")
+	}
+
+	err = NewResponse().
+		RedirectWithFlash("/", "bad", make(chan int)).
+		LocationWithContext("/hello", LocationContext{}).
+		Err()
+	if err == nil {
+		t.Errorf("expected the flash error to be kept after LocationWithContext")
+	}
+}
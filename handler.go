@@ -0,0 +1,113 @@
+package htmx
+
+import (
+	"net/http"
+)
+
+// Handler binds a request and its response writer together, so HTMX request
+// information can be read and responses written without passing them around.
+//
+// The request headers are read once with [htmx.Snapshot] when the Handler is created.
+type Handler struct {
+	w http.ResponseWriter
+	r *http.Request
+	s RequestSnapshot
+}
+
+// NewHandler returns a new Handler for the given response writer and request.
+//
+// Example:
+//
+//	h := htmx.NewHandler(w, r)
+//	if h.IsHTMX() {
+//		h.MustWrite(htmx.NewResponse().Reswap(htmx.SwapOuterHTML))
+//	}
+func NewHandler(w http.ResponseWriter, r *http.Request) *Handler {
+	return &Handler{
+		w: w,
+		r: r,
+		s: Snapshot(r),
+	}
+}
+
+// Request returns the request of this handler.
+func (h *Handler) Request() *http.Request {
+	return h.r
+}
+
+// ResponseWriter returns the response writer of this handler.
+func (h *Handler) ResponseWriter() http.ResponseWriter {
+	return h.w
+}
+
+// IsHTMX returns true if the request was made by HTMX.
+//
+// See [htmx.IsHTMX].
+func (h *Handler) IsHTMX() bool {
+	return h.s.IsHTMX
+}
+
+// IsBoosted returns true if the request was made via an element using 'hx-boost'.
+//
+// See [htmx.IsBoosted].
+func (h *Handler) IsBoosted() bool {
+	return h.s.IsBoosted
+}
+
+// IsHistoryRestoreRequest returns true if the request is for history restoration
+// after a miss in the local history cache.
+//
+// See [htmx.IsHistoryRestoreRequest].
+func (h *Handler) IsHistoryRestoreRequest() bool {
+	return h.s.IsHistoryRestore
+}
+
+// GetCurrentURL returns the current URL that HTMX made the request from.
+//
+// See [htmx.GetCurrentURL].
+func (h *Handler) GetCurrentURL() (string, bool) {
+	return h.s.CurrentURL, h.s.CurrentURLPresent
+}
+
+// GetPrompt returns the user response to an hx-prompt.
+//
+// See [htmx.GetPrompt].
+func (h *Handler) GetPrompt() (string, bool) {
+	return h.s.Prompt, h.s.PromptPresent
+}
+
+// GetTarget returns the ID of the target element if it exists.
+//
+// See [htmx.GetTarget].
+func (h *Handler) GetTarget() (string, bool) {
+	return h.s.Target, h.s.TargetPresent
+}
+
+// GetTriggerName returns the 'name' of the triggered element if it exists.
+//
+// See [htmx.GetTriggerName].
+func (h *Handler) GetTriggerName() (string, bool) {
+	return h.s.TriggerName, h.s.TriggerNamePresent
+}
+
+// GetTrigger returns the ID of the triggered element if it exists.
+//
+// See [htmx.GetTrigger].
+func (h *Handler) GetTrigger() (string, bool) {
+	return h.s.TriggerID, h.s.TriggerIDPresent
+}
+
+// Write applies the headers of the given response to the response writer of this handler.
+//
+// Under the hood this uses [htmx.Response.Write].
+func (h *Handler) Write(res Response) error {
+	return res.Write(h.w)
+}
+
+// MustWrite applies the headers of the given response to the response writer of this handler,
+// otherwise it panics.
+//
+// Under the hood this uses [htmx.Response.MustWrite].
+func (h *Handler) MustWrite(res Response) {
+	res.MustWrite(h.w)
+}
@@ -0,0 +1,38 @@
+package htmx
+
+import (
+	"testing"
+)
+
+func TestHandler(t *testing.T) {
+	r := newHTMXRequest(map[string]string{
+		HeaderRequest:     "true",
+		HeaderBoosted:     "true",
+		HeaderTarget:      "main",
+		HeaderTriggerName: "save",
+	})
+	w := newMockResponseWriter()
+
+	h := NewHandler(w, r)
+
+	if !h.IsHTMX() || !h.IsBoosted() || h.IsHistoryRestoreRequest() {
+		t.Errorf("wrong request predicates. htmx=%v, boosted=%v, history restore=%v",
+			h.IsHTMX(), h.IsBoosted(), h.IsHistoryRestoreRequest())
+	}
+
+	if got, ok := h.GetTarget(); !ok || got != "main" {
+		t.Errorf("wrong target. got=%q, %v, want=%q, %v", got, ok, "main", true)
+	}
+	if got, ok := h.GetTriggerName(); !ok || got != "save" {
+		t.Errorf("wrong trigger name. got=%q, %v, want=%q, %v", got, ok, "save", true)
+	}
+	if _, ok := h.GetPrompt(); ok {
+		t.Errorf("expected no prompt")
+	}
+
+	h.MustWrite(NewResponse().Reswap(SwapOuterHTML))
+
+	if got := w.header.Get(HeaderReswap); got != "outerHTML" {
+		t.Errorf("wrong value for header %q. got=%q, want=%q", HeaderReswap, got, "outerHTML")
+	}
+}
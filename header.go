@@ -17,6 +17,9 @@ const (
 	HeaderTarget = "HX-Target"
 	// Request header of the name of the triggered element if it exists.
 	HeaderTriggerName = "Hx-Trigger-Name"
+	// Request header with a JSON representation of the event that triggered the request.
+	// Only sent when the 'event-header' extension is enabled.
+	HeaderTriggeringEvent = "Triggering-Event"
 )
 
 // Common HTTP headers
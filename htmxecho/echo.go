@@ -0,0 +1,129 @@
+// Package htmxecho adapts htmx-go to the Echo web framework.
+//
+// It is a separate module, so the core htmx-go package stays free of dependencies.
+package htmxecho
+
+import (
+	"github.com/angelofallars/htmx-go"
+	"github.com/labstack/echo/v4"
+)
+
+// ContextKey is the key of the [Helper] stored in an Echo context by [Middleware].
+const ContextKey = "htmx"
+
+// IsHTMX returns true if the request of an Echo context was made by HTMX.
+//
+// Under the hood this uses [htmx.IsHTMX].
+func IsHTMX(c echo.Context) bool {
+	return htmx.IsHTMX(c.Request())
+}
+
+// Write applies the defined HTMX headers of a response to the response writer
+// of an Echo context.
+//
+// Under the hood this uses [htmx.Response.Write].
+func Write(c echo.Context, r htmx.Response) error {
+	return r.Write(c.Response())
+}
+
+// Middleware stores a [Helper] for each Echo context, which can be retrieved with [FromContext].
+func Middleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		c.Set(ContextKey, newHelper(c))
+		return next(c)
+	}
+}
+
+// FromContext returns the [Helper] stored in an Echo context by [Middleware].
+//
+// If the context does not come from a request handled by the middleware,
+// a new helper is returned.
+func FromContext(c echo.Context) *Helper {
+	if h, ok := c.Get(ContextKey).(*Helper); ok {
+		return h
+	}
+	return newHelper(c)
+}
+
+// Helper binds an Echo context to htmx-go, so HTMX request information can be read
+// and responses written without passing the context around.
+type Helper struct {
+	c echo.Context
+}
+
+func newHelper(c echo.Context) *Helper {
+	return &Helper{c: c}
+}
+
+// IsHTMX returns true if the request was made by HTMX.
+//
+// See [htmx.IsHTMX].
+func (h *Helper) IsHTMX() bool {
+	return htmx.IsHTMX(h.c.Request())
+}
+
+// IsBoosted returns true if the request was made via an element using 'hx-boost'.
+//
+// See [htmx.IsBoosted].
+func (h *Helper) IsBoosted() bool {
+	return htmx.IsBoosted(h.c.Request())
+}
+
+// IsHistoryRestoreRequest returns true if the request is for history restoration
+// after a miss in the local history cache.
+//
+// See [htmx.IsHistoryRestoreRequest].
+func (h *Helper) IsHistoryRestoreRequest() bool {
+	return htmx.IsHistoryRestoreRequest(h.c.Request())
+}
+
+// GetCurrentURL returns the current URL that HTMX made the request from.
+//
+// See [htmx.GetCurrentURL].
+func (h *Helper) GetCurrentURL() (string, bool) {
+	return htmx.GetCurrentURL(h.c.Request())
+}
+
+// GetPrompt returns the user response to an hx-prompt.
+//
+// See [htmx.GetPrompt].
+func (h *Helper) GetPrompt() (string, bool) {
+	return htmx.GetPrompt(h.c.Request())
+}
+
+// GetTarget returns the ID of the target element if it exists.
+//
+// See [htmx.GetTarget].
+func (h *Helper) GetTarget() (string, bool) {
+	return htmx.GetTarget(h.c.Request())
+}
+
+// GetTriggerName returns the 'name' of the triggered element if it exists.
+//
+// See [htmx.GetTriggerName].
+func (h *Helper) GetTriggerName() (string, bool) {
+	return htmx.GetTriggerName(h.c.Request())
+}
+
+// GetTrigger returns the ID of the triggered element if it exists.
+//
+// See [htmx.GetTrigger].
+func (h *Helper) GetTrigger() (string, bool) {
+	return htmx.GetTrigger(h.c.Request())
+}
+
+// Write applies the defined HTMX headers of a response to the response writer
+// of the Echo context.
+//
+// Under the hood this uses [htmx.Response.Write].
+func (h *Helper) Write(r htmx.Response) error {
+	return Write(h.c, r)
+}
+
+// MustWrite applies the defined HTMX headers of a response to the response writer
+// of the Echo context, otherwise it panics.
+//
+// Under the hood this uses [htmx.Response.MustWrite].
+func (h *Helper) MustWrite(r htmx.Response) {
+	r.MustWrite(h.c.Response())
+}
@@ -0,0 +1,86 @@
+package htmxecho
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/angelofallars/htmx-go"
+	"github.com/labstack/echo/v4"
+)
+
+func TestWrite(t *testing.T) {
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+
+	err := Write(c, htmx.NewResponse().
+		StatusCode(http.StatusCreated).
+		Retarget("#contacts"))
+	if err != nil {
+		t.Errorf("an error occurred writing a response: %v", err)
+	}
+
+	if got := rec.Header().Get(htmx.HeaderRetarget); got != "#contacts" {
+		t.Errorf("wrong value for header %q. got=%q, want=%q", htmx.HeaderRetarget, got, "#contacts")
+	}
+	if rec.Code != http.StatusCreated {
+		t.Errorf("wrong status code. want=%v, got=%v", http.StatusCreated, rec.Code)
+	}
+}
+
+func TestIsHTMX(t *testing.T) {
+	testCases := []struct {
+		name    string
+		headers map[string]string
+		want    bool
+	}{
+		{name: "HTMX request", headers: map[string]string{htmx.HeaderRequest: "true"}, want: true},
+		{name: "normal request", headers: nil, want: false},
+	}
+
+	for _, tc := range testCases {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		for k, v := range tc.headers {
+			req.Header.Set(k, v)
+		}
+		c := echo.New().NewContext(req, httptest.NewRecorder())
+
+		if got := IsHTMX(c); got != tc.want {
+			t.Errorf(`%s: got: "%v", want: "%v"`, tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(htmx.HeaderRequest, "true")
+	req.Header.Set(htmx.HeaderTarget, "contacts")
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(req, rec)
+
+	handler := Middleware(func(c echo.Context) error {
+		h := FromContext(c)
+		if h != c.Get(ContextKey) {
+			t.Errorf("helper is not the one stored by the middleware")
+		}
+		if target, ok := h.GetTarget(); !h.IsHTMX() || !ok || target != "contacts" {
+			t.Errorf("wrong request info. got=%v, %q, %v", h.IsHTMX(), target, ok)
+		}
+		return h.Write(htmx.NewResponse().Reswap(htmx.SwapOuterHTML))
+	})
+
+	if err := handler(c); err != nil {
+		t.Errorf("an error occurred handling a request: %v", err)
+	}
+	if got := rec.Header().Get(htmx.HeaderReswap); got != "outerHTML" {
+		t.Errorf("wrong value for header %q. got=%q, want=%q", htmx.HeaderReswap, got, "outerHTML")
+	}
+}
+
+func TestFromContext_WithoutMiddleware(t *testing.T) {
+	c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/", nil), httptest.NewRecorder())
+
+	if h := FromContext(c); h == nil || h.IsHTMX() {
+		t.Errorf("expected a new helper for a non-HTMX request, got %v", h)
+	}
+}
@@ -0,0 +1,94 @@
+// Package htmxfiber adapts htmx-go to the Fiber web framework.
+//
+// Fiber is built on fasthttp instead of net/http, so this package maps requests
+// and responses between the two. It is a separate module, so the core htmx-go
+// package stays free of dependencies.
+package htmxfiber
+
+import (
+	"net/http"
+
+	"github.com/angelofallars/htmx-go"
+	"github.com/gofiber/fiber/v2"
+)
+
+// IsHTMX returns true if the request of a Fiber context was made by HTMX.
+//
+// Under the hood this uses [htmx.IsHTMX].
+func IsHTMX(c *fiber.Ctx) bool {
+	return htmx.IsHTMX(request(c))
+}
+
+// IsBoosted returns true if the request of a Fiber context was made via an element using 'hx-boost'.
+//
+// Under the hood this uses [htmx.IsBoosted].
+func IsBoosted(c *fiber.Ctx) bool {
+	return htmx.IsBoosted(request(c))
+}
+
+// IsHistoryRestoreRequest returns true if the request of a Fiber context is for history
+// restoration after a miss in the local history cache.
+//
+// Under the hood this uses [htmx.IsHistoryRestoreRequest].
+func IsHistoryRestoreRequest(c *fiber.Ctx) bool {
+	return htmx.IsHistoryRestoreRequest(request(c))
+}
+
+// Write applies the defined HTMX headers, cookies and status code of a response
+// to a Fiber context.
+//
+// Errors from building the response, such as triggers that can't be marshalled,
+// are returned and nothing is applied.
+func Write(c *fiber.Ctx, r htmx.Response) error {
+	w := &headerWriter{header: http.Header{}}
+	for _, v := range c.Response().Header.PeekAll(fiber.HeaderVary) {
+		w.header.Add(fiber.HeaderVary, string(v))
+	}
+
+	if err := r.Write(w); err != nil {
+		return err
+	}
+
+	header := &c.Response().Header
+	for k, values := range w.header {
+		for i, v := range values {
+			// Cookies are added to the ones already set on the context
+			if i == 0 && k != fiber.HeaderSetCookie {
+				header.Set(k, v)
+				continue
+			}
+			header.Add(k, v)
+		}
+	}
+
+	if w.statusCode != 0 {
+		c.Status(w.statusCode)
+	}
+
+	return nil
+}
+
+// request returns a net/http request with the headers of a Fiber context,
+// for the request helpers of the core package
+func request(c *fiber.Ctx) *http.Request {
+	return &http.Request{Header: http.Header(c.GetReqHeaders())}
+}
+
+// headerWriter records the headers and status code written by [htmx.Response.Write],
+// to apply them to a Fiber context.
+type headerWriter struct {
+	header     http.Header
+	statusCode int
+}
+
+func (w *headerWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *headerWriter) Write(b []byte) (int, error) {
+	return len(b), nil
+}
+
+func (w *headerWriter) WriteHeader(statusCode int) {
+	w.statusCode = statusCode
+}
@@ -0,0 +1,129 @@
+package htmxfiber
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/angelofallars/htmx-go"
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestWrite(t *testing.T) {
+	app := fiber.New()
+	app.Get("/", func(c *fiber.Ctx) error {
+		c.Set(fiber.HeaderVary, "Accept")
+		return Write(c, htmx.NewResponse().
+			StatusCode(http.StatusCreated).
+			Retarget("#contacts").
+			AddTrigger(htmx.TriggerDetail("showMessage", "Saved")).
+			Cookie(&http.Cookie{Name: "session", Value: "abc"}).
+			Cookie(&http.Cookie{Name: "theme", Value: "dark"}).
+			VaryOnHTMX())
+	})
+
+	res, err := app.Test(httptest.NewRequest(http.MethodGet, "/", nil))
+	if err != nil {
+		t.Fatalf("an error occurred making a request: %v", err)
+	}
+
+	if res.StatusCode != http.StatusCreated {
+		t.Errorf("wrong status code. want=%v, got=%v", http.StatusCreated, res.StatusCode)
+	}
+
+	expectedHeaders := map[string]string{
+		htmx.HeaderRetarget: "#contacts",
+		htmx.HeaderTrigger:  `{"showMessage":"Saved"}`,
+		"Vary":              "Accept, HX-Request",
+	}
+	for k, v := range expectedHeaders {
+		if got := res.Header.Get(k); got != v {
+			t.Errorf("wrong value for header %q. got=%q, want=%q", k, got, v)
+		}
+	}
+
+	if got := len(res.Cookies()); got != 2 {
+		t.Errorf("wrong number of cookies. got=%d, want=%d", got, 2)
+	}
+}
+
+func TestWrite_Error(t *testing.T) {
+	var writeErr error
+
+	app := fiber.New()
+	app.Get("/", func(c *fiber.Ctx) error {
+		writeErr = Write(c, htmx.NewResponse().
+			Retarget("#contacts").
+			AddTrigger(htmx.TriggerObject("bad", make(chan int))))
+		return c.SendStatus(http.StatusInternalServerError)
+	})
+
+	res, err := app.Test(httptest.NewRequest(http.MethodGet, "/", nil))
+	if err != nil {
+		t.Fatalf("an error occurred making a request: %v", err)
+	}
+
+	if writeErr == nil {
+		t.Errorf("expected a trigger marshalling error")
+	}
+	if got := res.Header.Get(htmx.HeaderRetarget); got != "" {
+		t.Errorf("header %q should not be set for a failed write, got=%q", htmx.HeaderRetarget, got)
+	}
+}
+
+func TestPredicates(t *testing.T) {
+	app := fiber.New()
+	app.Get("/", func(c *fiber.Ctx) error {
+		return c.SendString(strconv.FormatBool(IsHTMX(c)) + " " +
+			strconv.FormatBool(IsBoosted(c)) + " " +
+			strconv.FormatBool(IsHistoryRestoreRequest(c)))
+	})
+
+	testCases := []struct {
+		name    string
+		headers map[string]string
+		want    string
+	}{
+		{
+			name:    "normal request",
+			headers: nil,
+			want:    "false false false",
+		},
+		{
+			name:    "HTMX request",
+			headers: map[string]string{htmx.HeaderRequest: "true"},
+			want:    "true false false",
+		},
+		{
+			name: "boosted history restore request",
+			headers: map[string]string{
+				htmx.HeaderRequest:               "true",
+				htmx.HeaderBoosted:               "true",
+				htmx.HeaderHistoryRestoreRequest: "true",
+			},
+			want: "true true true",
+		},
+	}
+
+	for _, tc := range testCases {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		for k, v := range tc.headers {
+			req.Header.Set(k, v)
+		}
+
+		res, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("%s: an error occurred making a request: %v", tc.name, err)
+		}
+
+		body, err := io.ReadAll(res.Body)
+		if err != nil {
+			t.Fatalf("%s: an error occurred reading the body: %v", tc.name, err)
+		}
+		if got := string(body); got != tc.want {
+			t.Errorf(`%s: got: "%v", want: "%v"`, tc.name, got, tc.want)
+		}
+	}
+}
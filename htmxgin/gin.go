@@ -0,0 +1,24 @@
+// Package htmxgin adapts htmx-go to the Gin web framework.
+//
+// It is a separate module, so the core htmx-go package stays free of dependencies.
+package htmxgin
+
+import (
+	"github.com/angelofallars/htmx-go"
+	"github.com/gin-gonic/gin"
+)
+
+// Write applies the defined HTMX headers of a response to the response writer
+// of a Gin context.
+//
+// Under the hood this uses [htmx.Response.Write].
+func Write(c *gin.Context, r htmx.Response) error {
+	return r.Write(c.Writer)
+}
+
+// IsHTMX returns true if the request of a Gin context was made by HTMX.
+//
+// Under the hood this uses [htmx.IsHTMX].
+func IsHTMX(c *gin.Context) bool {
+	return htmx.IsHTMX(c.Request)
+}
@@ -0,0 +1,57 @@
+package htmxgin
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/angelofallars/htmx-go"
+	"github.com/gin-gonic/gin"
+)
+
+func TestWrite(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	rec := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(rec)
+
+	err := Write(c, htmx.NewResponse().
+		StatusCode(http.StatusCreated).
+		Retarget("#contacts"))
+	if err != nil {
+		t.Errorf("an error occurred writing a response: %v", err)
+	}
+
+	if got := rec.Header().Get(htmx.HeaderRetarget); got != "#contacts" {
+		t.Errorf("wrong value for header %q. got=%q, want=%q", htmx.HeaderRetarget, got, "#contacts")
+	}
+	// Gin writes the status code when the handler returns
+	if got := c.Writer.Status(); got != http.StatusCreated {
+		t.Errorf("wrong status code. want=%v, got=%v", http.StatusCreated, got)
+	}
+}
+
+func TestIsHTMX(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	testCases := []struct {
+		name    string
+		headers map[string]string
+		want    bool
+	}{
+		{name: "HTMX request", headers: map[string]string{htmx.HeaderRequest: "true"}, want: true},
+		{name: "normal request", headers: nil, want: false},
+	}
+
+	for _, tc := range testCases {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+		for k, v := range tc.headers {
+			c.Request.Header.Set(k, v)
+		}
+
+		if got := IsHTMX(c); got != tc.want {
+			t.Errorf(`%s: got: "%v", want: "%v"`, tc.name, got, tc.want)
+		}
+	}
+}
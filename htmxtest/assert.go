@@ -0,0 +1,92 @@
+// Package htmxtest provides helpers for testing handlers that use htmx-go.
+package htmxtest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/angelofallars/htmx-go"
+)
+
+// AssertReswap checks that the 'HX-Reswap' header of a recorded response is the expected swap strategy.
+func AssertReswap(t testing.TB, rec *httptest.ResponseRecorder, expected htmx.SwapStrategy) {
+	t.Helper()
+	assertHeader(t, rec, htmx.HeaderReswap, expected.String())
+}
+
+// AssertRedirect checks that the 'HX-Redirect' header of a recorded response is the expected path.
+func AssertRedirect(t testing.TB, rec *httptest.ResponseRecorder, path string) {
+	t.Helper()
+	assertHeader(t, rec, htmx.HeaderRedirect, path)
+}
+
+// AssertTrigger checks that the 'HX-Trigger' header of a recorded response triggers the given event,
+// with or without details.
+func AssertTrigger(t testing.TB, rec *httptest.ResponseRecorder, event string) {
+	t.Helper()
+
+	value, ok := rec.Header()[http.CanonicalHeaderKey(htmx.HeaderTrigger)]
+	if !ok {
+		t.Errorf("header %q is not set, want event %q", htmx.HeaderTrigger, event)
+		return
+	}
+
+	events, err := triggerEvents(strings.Join(value, ", "))
+	if err != nil {
+		t.Errorf("header %q is malformed: %v", htmx.HeaderTrigger, err)
+		return
+	}
+
+	for _, e := range events {
+		if e == event {
+			return
+		}
+	}
+	t.Errorf("header %q does not trigger event %q. got=%q", htmx.HeaderTrigger, event, value)
+}
+
+// AssertStatus checks that the status code of a recorded response is the expected code.
+func AssertStatus(t testing.TB, rec *httptest.ResponseRecorder, code int) {
+	t.Helper()
+	if rec.Code != code {
+		t.Errorf("wrong status code. got=%d, want=%d", rec.Code, code)
+	}
+}
+
+func assertHeader(t testing.TB, rec *httptest.ResponseRecorder, key string, want string) {
+	t.Helper()
+
+	if _, ok := rec.Header()[http.CanonicalHeaderKey(key)]; !ok {
+		t.Errorf("header %q is not set, want %q", key, want)
+		return
+	}
+	if got := rec.Header().Get(key); got != want {
+		t.Errorf("wrong value for header %q. got=%q, want=%q", key, got, want)
+	}
+}
+
+// triggerEvents returns the event names of a trigger header value,
+// which is either a comma-separated list of events or a JSON object of events and details.
+func triggerEvents(value string) ([]string, error) {
+	if !strings.HasPrefix(strings.TrimSpace(value), "{") {
+		var events []string
+		for _, e := range strings.Split(value, ",") {
+			events = append(events, strings.TrimSpace(e))
+		}
+		return events, nil
+	}
+
+	var m map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(value), &m); err != nil {
+		return nil, err
+	}
+
+	events := make([]string, 0, len(m))
+	for e := range m {
+		events = append(events, e)
+	}
+	return events, nil
+}
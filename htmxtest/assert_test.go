@@ -0,0 +1,114 @@
+package htmxtest
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/angelofallars/htmx-go"
+)
+
+// recordingT records the failures of an assertion instead of failing the test.
+type recordingT struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recordingT) Helper() {}
+
+func (r *recordingT) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestAssertions(t *testing.T) {
+	rec := httptest.NewRecorder()
+	htmx.NewResponse().
+		StatusCode(http.StatusCreated).
+		Reswap(htmx.SwapBeforeEnd.Scroll(htmx.Bottom)).
+		Redirect("/contacts").
+		AddTrigger(htmx.Trigger("contactAdded"), htmx.TriggerDetail("showMessage", "Saved")).
+		MustWrite(rec)
+
+	plainRec := httptest.NewRecorder()
+	htmx.NewResponse().
+		AddTrigger(htmx.Trigger("a"), htmx.Trigger("b")).
+		MustWrite(plainRec)
+
+	emptyRec := httptest.NewRecorder()
+	htmx.NewResponse().MustWrite(emptyRec)
+
+	testCases := []struct {
+		name      string
+		assert    func(t testing.TB)
+		wantError string
+	}{
+		{
+			name:   "reswap",
+			assert: func(t testing.TB) { AssertReswap(t, rec, htmx.SwapBeforeEnd.Scroll(htmx.Bottom)) },
+		},
+		{
+			name:      "wrong reswap",
+			assert:    func(t testing.TB) { AssertReswap(t, rec, htmx.SwapInnerHTML) },
+			wantError: `"HX-Reswap"`,
+		},
+		{
+			name:      "missing reswap",
+			assert:    func(t testing.TB) { AssertReswap(t, emptyRec, htmx.SwapInnerHTML) },
+			wantError: `"HX-Reswap" is not set`,
+		},
+		{
+			name:   "redirect",
+			assert: func(t testing.TB) { AssertRedirect(t, rec, "/contacts") },
+		},
+		{
+			name:      "wrong redirect",
+			assert:    func(t testing.TB) { AssertRedirect(t, rec, "/login") },
+			wantError: `"HX-Redirect"`,
+		},
+		{
+			name:   "trigger in JSON",
+			assert: func(t testing.TB) { AssertTrigger(t, rec, "showMessage") },
+		},
+		{
+			name:   "trigger in list",
+			assert: func(t testing.TB) { AssertTrigger(t, plainRec, "b") },
+		},
+		{
+			name:      "missing trigger",
+			assert:    func(t testing.TB) { AssertTrigger(t, rec, "contactDeleted") },
+			wantError: `"HX-Trigger" does not trigger event "contactDeleted"`,
+		},
+		{
+			name:      "missing trigger header",
+			assert:    func(t testing.TB) { AssertTrigger(t, emptyRec, "contactAdded") },
+			wantError: `"HX-Trigger" is not set`,
+		},
+		{
+			name:   "status",
+			assert: func(t testing.TB) { AssertStatus(t, rec, http.StatusCreated) },
+		},
+		{
+			name:      "wrong status",
+			assert:    func(t testing.TB) { AssertStatus(t, rec, http.StatusOK) },
+			wantError: "wrong status code",
+		},
+	}
+
+	for _, tc := range testCases {
+		rt := &recordingT{TB: t}
+		tc.assert(rt)
+
+		if tc.wantError == "" {
+			if len(rt.errors) != 0 {
+				t.Errorf("%s: unexpected failure: %v", tc.name, rt.errors)
+			}
+			continue
+		}
+
+		if len(rt.errors) != 1 || !strings.Contains(rt.errors[0], tc.wantError) {
+			t.Errorf("%s: got failures %q, want one containing %q", tc.name, rt.errors, tc.wantError)
+		}
+	}
+}
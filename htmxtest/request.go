@@ -0,0 +1,96 @@
+package htmxtest
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/angelofallars/htmx-go"
+)
+
+// RequestOption sets HTMX request headers on a request built by [NewRequest].
+type RequestOption func(r *http.Request)
+
+// NewRequest returns a new incoming server request for testing, like [httptest.NewRequest],
+// with the HTMX request headers set by the given options.
+//
+// Example:
+//
+//	req := htmxtest.NewRequest(http.MethodGet, "/contacts",
+//		htmxtest.WithHTMX(),
+//		htmxtest.WithTarget("contact-list"),
+//	)
+func NewRequest(method string, target string, opts ...RequestOption) *http.Request {
+	r := httptest.NewRequest(method, target, nil)
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// WithHTMX marks the request as made by HTMX.
+//
+// Sets the 'HX-Request' header.
+func WithHTMX() RequestOption {
+	return withHeader(htmx.HeaderRequest, "true")
+}
+
+// WithBoosted marks the request as made by HTMX via an element using 'hx-boost'.
+//
+// Sets the 'HX-Request' and 'HX-Boosted' headers.
+func WithBoosted() RequestOption {
+	return func(r *http.Request) {
+		r.Header.Set(htmx.HeaderRequest, "true")
+		r.Header.Set(htmx.HeaderBoosted, "true")
+	}
+}
+
+// WithHistoryRestoreRequest marks the request as made by HTMX for history restoration.
+//
+// Sets the 'HX-Request' and 'HX-History-Restore-Request' headers.
+func WithHistoryRestoreRequest() RequestOption {
+	return func(r *http.Request) {
+		r.Header.Set(htmx.HeaderRequest, "true")
+		r.Header.Set(htmx.HeaderHistoryRestoreRequest, "true")
+	}
+}
+
+// WithCurrentURL sets the current URL of the browser.
+//
+// Sets the 'HX-Current-URL' header.
+func WithCurrentURL(u string) RequestOption {
+	return withHeader(htmx.HeaderCurrentURL, u)
+}
+
+// WithTarget sets the ID of the target element.
+//
+// Sets the 'HX-Target' header.
+func WithTarget(id string) RequestOption {
+	return withHeader(htmx.HeaderTarget, id)
+}
+
+// WithPrompt sets the user response to an hx-prompt.
+//
+// Sets the 'HX-Prompt' header.
+func WithPrompt(s string) RequestOption {
+	return withHeader(htmx.HeaderPrompt, s)
+}
+
+// WithTrigger sets the ID of the triggered element.
+//
+// Sets the 'HX-Trigger' header.
+func WithTrigger(id string) RequestOption {
+	return withHeader(htmx.HeaderTrigger, id)
+}
+
+// WithTriggerName sets the 'name' of the triggered element.
+//
+// Sets the 'HX-Trigger-Name' header.
+func WithTriggerName(name string) RequestOption {
+	return withHeader(htmx.HeaderTriggerName, name)
+}
+
+func withHeader(key string, value string) RequestOption {
+	return func(r *http.Request) {
+		r.Header.Set(key, value)
+	}
+}
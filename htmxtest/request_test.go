@@ -0,0 +1,85 @@
+package htmxtest
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/angelofallars/htmx-go"
+)
+
+func TestNewRequest(t *testing.T) {
+	r := NewRequest(http.MethodPost, "/contacts",
+		WithHTMX(),
+		WithCurrentURL("https://example.com/contacts"),
+		WithTarget("contact-list"),
+		WithPrompt("Yes"),
+		WithTrigger("save-button"),
+		WithTriggerName("save"),
+	)
+
+	if r.Method != http.MethodPost || r.URL.Path != "/contacts" {
+		t.Errorf("wrong request. got=%s %s, want=%s %s", r.Method, r.URL.Path, http.MethodPost, "/contacts")
+	}
+
+	want := htmx.RequestSnapshot{
+		IsHTMX:             true,
+		CurrentURL:         "https://example.com/contacts",
+		CurrentURLPresent:  true,
+		Prompt:             "Yes",
+		PromptPresent:      true,
+		Target:             "contact-list",
+		TargetPresent:      true,
+		TriggerName:        "save",
+		TriggerNamePresent: true,
+		TriggerID:          "save-button",
+		TriggerIDPresent:   true,
+	}
+	if got := htmx.Snapshot(r); got != want {
+		t.Errorf("wrong request headers. got=%+v, want=%+v", got, want)
+	}
+}
+
+func TestNewRequest_Predicates(t *testing.T) {
+	testCases := []struct {
+		name   string
+		result bool
+		want   bool
+	}{
+		{
+			name:   "no options",
+			result: htmx.IsHTMX(NewRequest(http.MethodGet, "/")),
+			want:   false,
+		},
+		{
+			name:   "WithHTMX",
+			result: htmx.IsHTMX(NewRequest(http.MethodGet, "/", WithHTMX())),
+			want:   true,
+		},
+		{
+			name:   "WithBoosted is HTMX",
+			result: htmx.IsHTMX(NewRequest(http.MethodGet, "/", WithBoosted())),
+			want:   true,
+		},
+		{
+			name:   "WithBoosted",
+			result: htmx.IsBoosted(NewRequest(http.MethodGet, "/", WithBoosted())),
+			want:   true,
+		},
+		{
+			name:   "WithHistoryRestoreRequest",
+			result: htmx.IsHistoryRestoreRequest(NewRequest(http.MethodGet, "/", WithHistoryRestoreRequest())),
+			want:   true,
+		},
+		{
+			name:   "WithTarget",
+			result: htmx.TargetIs(NewRequest(http.MethodGet, "/", WithHTMX(), WithTarget("main")), "#main"),
+			want:   true,
+		},
+	}
+
+	for _, tc := range testCases {
+		if tc.result != tc.want {
+			t.Errorf(`%s: got: "%v", want: "%v"`, tc.name, tc.result, tc.want)
+		}
+	}
+}
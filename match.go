@@ -0,0 +1,86 @@
+package htmx
+
+import (
+	"net/http"
+)
+
+// Matcher runs one of several branches depending on the kind of request,
+// created with [htmx.Match].
+type Matcher struct {
+	r              *http.Request
+	historyRestore func()
+	boosted        func()
+	htmx           func()
+	fallback       func()
+}
+
+// Match returns a [htmx.Matcher] for declaratively branching on the kind of request.
+//
+// Branches are checked in this order of precedence, regardless of the order they are added in:
+//
+//  1. HistoryRestore, for history restoration requests ([htmx.IsHistoryRestoreRequest]).
+//  2. Boosted, for requests made via 'hx-boost' ([htmx.IsBoosted]).
+//  3. HTMX, for any request made by HTMX ([htmx.IsHTMX]).
+//  4. Default, for any other request.
+//
+// Exec runs the first branch that was added and matches the request. Since history restoration
+// and boosted requests are also HTMX requests, they run the HTMX branch if their own branch
+// is not added.
+//
+// Example:
+//
+//	htmx.Match(r).
+//		Boosted(renderPage).
+//		HTMX(renderFragment).
+//		Default(renderPage).
+//		Exec()
+func Match(r *http.Request) *Matcher {
+	return &Matcher{r: r}
+}
+
+// HistoryRestore sets the branch for history restoration requests.
+func (m *Matcher) HistoryRestore(fn func()) *Matcher {
+	m.historyRestore = fn
+	return m
+}
+
+// Boosted sets the branch for requests made via an element using 'hx-boost'.
+func (m *Matcher) Boosted(fn func()) *Matcher {
+	m.boosted = fn
+	return m
+}
+
+// HTMX sets the branch for requests made by HTMX.
+func (m *Matcher) HTMX(fn func()) *Matcher {
+	m.htmx = fn
+	return m
+}
+
+// Default sets the branch for requests that match no other added branch.
+func (m *Matcher) Default(fn func()) *Matcher {
+	m.fallback = fn
+	return m
+}
+
+// Exec runs the first matching branch in order of precedence.
+//
+// Returns false if no branch ran.
+func (m *Matcher) Exec() bool {
+	branches := []struct {
+		matches bool
+		fn      func()
+	}{
+		{IsHistoryRestoreRequest(m.r), m.historyRestore},
+		{IsBoosted(m.r), m.boosted},
+		{IsHTMX(m.r), m.htmx},
+		{true, m.fallback},
+	}
+
+	for _, b := range branches {
+		if b.matches && b.fn != nil {
+			b.fn()
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,72 @@
+package htmx
+
+import (
+	"testing"
+)
+
+func TestMatch(t *testing.T) {
+	testCases := []struct {
+		name    string
+		headers map[string]string
+		result  string
+	}{
+		{
+			name:    "history restore",
+			headers: map[string]string{HeaderRequest: "true", HeaderHistoryRestoreRequest: "true"},
+			result:  "history",
+		},
+		{
+			name:    "boosted",
+			headers: map[string]string{HeaderRequest: "true", HeaderBoosted: "true"},
+			result:  "boosted",
+		},
+		{
+			name:    "htmx",
+			headers: map[string]string{HeaderRequest: "true"},
+			result:  "htmx",
+		},
+		{
+			name:    "default",
+			headers: nil,
+			result:  "default",
+		},
+	}
+
+	for _, tc := range testCases {
+		var result string
+		branch := func(name string) func() {
+			return func() { result = name }
+		}
+
+		// Added in reverse order of precedence to check the order doesn't matter
+		ran := Match(newHTMXRequest(tc.headers)).
+			Default(branch("default")).
+			HTMX(branch("htmx")).
+			Boosted(branch("boosted")).
+			HistoryRestore(branch("history")).
+			Exec()
+
+		if !ran {
+			t.Errorf("%s: expected a branch to run", tc.name)
+		}
+		if result != tc.result {
+			t.Errorf(`%s: got: "%v", want: "%v"`, tc.name, result, tc.result)
+		}
+	}
+}
+
+func TestMatch_FallsThrough(t *testing.T) {
+	var result string
+
+	Match(newHTMXRequest(map[string]string{HeaderRequest: "true", HeaderBoosted: "true"})).
+		HTMX(func() { result = "htmx" }).
+		Exec()
+
+	if result != "htmx" {
+		t.Errorf(`got: "%v", want: "%v"`, result, "htmx")
+	}
+
+	if Match(newHTMXRequest(nil)).HTMX(func() {}).Exec() {
+		t.Errorf("expected no branch to run")
+	}
+}
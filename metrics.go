@@ -0,0 +1,56 @@
+package htmx
+
+import (
+	"net/http"
+	"sync/atomic"
+)
+
+// MetricsEvent describes a response written by [htmx.Response.Write],
+// passed to the hook set with [htmx.SetMetricsHook].
+type MetricsEvent struct {
+	// The HTTP status code of the response. 200 OK if no status code was set.
+	StatusCode int
+	// The 'HX-Reswap' value of the response, empty if not set.
+	Reswap string
+	// The total number of triggers in 'HX-Trigger', 'HX-Trigger-After-Settle'
+	// and 'HX-Trigger-After-Swap'.
+	TriggerCount int
+	// The error returned by Write, nil if the write succeeded.
+	Err error
+}
+
+// The hook set with SetMetricsHook, nil if unset
+var metricsHook atomic.Pointer[func(MetricsEvent)]
+
+// SetMetricsHook sets a function that is called every time [htmx.Response.Write] is called,
+// to record metrics about HTMX responses (e.g. with Prometheus).
+//
+// The hook may be called concurrently from multiple goroutines.
+// Passing nil removes the hook. By default, no hook is set.
+func SetMetricsHook(hook func(MetricsEvent)) {
+	if hook == nil {
+		metricsHook.Store(nil)
+		return
+	}
+	metricsHook.Store(&hook)
+}
+
+// recordMetrics calls the metrics hook if one is set.
+func (r Response) recordMetrics(err error) {
+	hook := metricsHook.Load()
+	if hook == nil {
+		return
+	}
+
+	statusCode := r.statusCode
+	if statusCode == 0 {
+		statusCode = http.StatusOK
+	}
+
+	(*hook)(MetricsEvent{
+		StatusCode:   statusCode,
+		Reswap:       r.headers[HeaderReswap],
+		TriggerCount: len(r.triggers) + len(r.triggersAfterSettle) + len(r.triggersAfterSwap),
+		Err:          err,
+	})
+}
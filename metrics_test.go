@@ -0,0 +1,45 @@
+package htmx
+
+import (
+	"testing"
+)
+
+func TestSetMetricsHook(t *testing.T) {
+	var events []MetricsEvent
+	SetMetricsHook(func(e MetricsEvent) {
+		events = append(events, e)
+	})
+	defer SetMetricsHook(nil)
+
+	w := newMockResponseWriter()
+
+	err := NewResponse().
+		StatusCode(StatusStopPolling).
+		Reswap(SwapOuterHTML).
+		AddTrigger(Trigger("a"), Trigger("b")).
+		AddTriggerAfterSwap(Trigger("c")).
+		Write(w)
+	if err != nil {
+		t.Errorf("an error occurred writing a response: %v", err)
+	}
+
+	err = NewResponse().
+		AddTrigger(TriggerObject("bad", make(chan int))).
+		Write(w)
+	if err == nil {
+		t.Errorf("expected an error writing an unserializable trigger")
+	}
+
+	if len(events) != 2 {
+		t.Fatalf("wrong number of metrics events. got=%v, want=%v", len(events), 2)
+	}
+
+	want := MetricsEvent{StatusCode: StatusStopPolling, Reswap: "outerHTML", TriggerCount: 3}
+	if events[0] != want {
+		t.Errorf("wrong metrics event. got=%+v, want=%+v", events[0], want)
+	}
+
+	if events[1].StatusCode != 200 || events[1].TriggerCount != 1 || events[1].Err == nil {
+		t.Errorf("wrong metrics event for failed write. got=%+v", events[1])
+	}
+}
@@ -0,0 +1,62 @@
+package htmx
+
+import (
+	"context"
+	"net/http"
+)
+
+// RequestInfo contains the HTMX request headers of a request,
+// stored in the request context by [htmx.Middleware].
+//
+// It is built from the [htmx.RequestSnapshot] of the request.
+// Fields of headers that do not exist are empty.
+type RequestInfo struct {
+	// Whether the request was made by HTMX.
+	IsHTMX bool
+	// Whether the request was made via an element using 'hx-boost'.
+	IsBoosted bool
+	// The current URL of the browser.
+	CurrentURL string
+	// The ID of the target element.
+	Target string
+	// The user response to an hx-prompt.
+	Prompt string
+	// The 'name' of the triggered element.
+	TriggerName string
+	// The ID of the triggered element.
+	TriggerID string
+}
+
+// Key for RequestInfo in a context
+type requestInfoKey struct{}
+
+// Middleware reads the HTMX request headers once and stores them in the request context
+// as a [htmx.RequestInfo], which can be retrieved with [htmx.FromContext].
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		info := newRequestInfo(Snapshot(r))
+		ctx := context.WithValue(r.Context(), requestInfoKey{}, info)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// FromContext returns the [htmx.RequestInfo] stored by [htmx.Middleware].
+//
+// Returns false if the context does not come from a request handled
+// by the middleware.
+func FromContext(ctx context.Context) (RequestInfo, bool) {
+	info, ok := ctx.Value(requestInfoKey{}).(RequestInfo)
+	return info, ok
+}
+
+func newRequestInfo(s RequestSnapshot) RequestInfo {
+	return RequestInfo{
+		IsHTMX:      s.IsHTMX,
+		IsBoosted:   s.IsBoosted,
+		CurrentURL:  s.CurrentURL,
+		Target:      s.Target,
+		Prompt:      s.Prompt,
+		TriggerName: s.TriggerName,
+		TriggerID:   s.TriggerID,
+	}
+}
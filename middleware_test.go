@@ -0,0 +1,43 @@
+package htmx
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestMiddleware(t *testing.T) {
+	r := newHTMXRequest(map[string]string{
+		HeaderRequest:    "true",
+		HeaderCurrentURL: "https://example.com/contacts",
+		HeaderTarget:     "contacts",
+		HeaderTrigger:    "load-more",
+	})
+
+	var (
+		info RequestInfo
+		ok   bool
+	)
+	handler := Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		info, ok = FromContext(r.Context())
+	}))
+	handler.ServeHTTP(newMockResponseWriter(), r)
+
+	if !ok {
+		t.Fatalf("expected request info in the context")
+	}
+
+	want := RequestInfo{
+		IsHTMX:     true,
+		CurrentURL: "https://example.com/contacts",
+		Target:     "contacts",
+		TriggerID:  "load-more",
+	}
+	if info != want {
+		t.Errorf("wrong request info. got=%+v, want=%+v", info, want)
+	}
+
+	if _, ok := FromContext(context.Background()); ok {
+		t.Errorf("expected no request info outside the middleware")
+	}
+}
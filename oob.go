@@ -0,0 +1,99 @@
+package htmx
+
+import (
+	"html/template"
+	"sort"
+)
+
+// OOB wraps an HTML fragment in an element that HTMX swaps out of band into
+// the element with the given ID, regardless of the target of the request.
+//
+// If swap is [SwapDefault], the fragment is swapped with 'outerHTML'.
+//
+// Example:
+//
+//	htmx.OOB("alerts", htmx.SwapBeforeEnd, "<p>Saved!</p>")
+//
+// Output:
+//
+//	<div id="alerts" hx-swap-oob="beforeend"><p>Saved!</p></div>
+//
+// For more info, see https://htmx.org/attributes/hx-swap-oob/
+func OOB(id string, swap SwapStrategy, html template.HTML) template.HTML {
+	if swap == SwapDefault {
+		swap = SwapOuterHTML
+	}
+
+	openTag := `<div id="` + template.HTMLEscapeString(id) +
+		`" hx-swap-oob="` + template.HTMLEscapeString(swap.swapString()) + `">`
+
+	return template.HTML(openTag) + html + template.HTML(`</div>`)
+}
+
+// AddOOB adds an HTML fragment that HTMX swaps out of band into the element with the given ID,
+// wrapped the same way as [htmx.OOB].
+//
+// The fragments are rendered in the order they were added, after the main content of
+// every method that writes an HTML body: [htmx.Response.RenderHTML],
+// [htmx.Response.RenderTempl] and the other Templ render methods, and [htmx.Response.StreamHTML],
+// which writes them when the stream is closed. Methods that write no body or a JSON body,
+// such as [htmx.Response.Write] and [htmx.Response.WriteJSON], don't render them.
+// This can be called multiple times, including with the same ID.
+//
+// For more info, see https://htmx.org/attributes/hx-swap-oob/
+func (r Response) AddOOB(id string, swap SwapStrategy, html template.HTML) Response {
+	// Limit the capacity so responses derived from the same response don't
+	// overwrite each other's fragments
+	r.oob = append(r.oob[:len(r.oob):len(r.oob)], OOB(id, swap, html))
+	return r
+}
+
+// FieldErrors returns an out-of-band fragment for each field error, swapping the message
+// into the element with the field's error ID with 'innerHTML', e.g. an error '<span>'
+// next to each form field.
+//
+// The keys of errs are the element IDs. The fragments are ordered by ID,
+// and an empty map returns an empty fragment.
+//
+// Example:
+//
+//	htmx.FieldErrors(map[string]template.HTML{
+//		"email-error": "Email is taken",
+//	})
+//
+// Output:
+//
+//	<div id="email-error" hx-swap-oob="innerHTML">Email is taken</div>
+//
+// For more info, see https://htmx.org/attributes/hx-swap-oob/
+func FieldErrors(errs map[string]template.HTML) template.HTML {
+	ids := make([]string, 0, len(errs))
+	for id := range errs {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	var body template.HTML
+	for _, id := range ids {
+		body += OOB(id, SwapInnerHTML, errs[id])
+	}
+	return body
+}
+
+// Preserve returns a placeholder for the element with the given ID that HTMX keeps
+// unchanged when swapping, e.g. a video player or an input with unsaved text.
+//
+// Include the placeholder in the swapped fragment where the element is, whether in the
+// main content or in the HTML of an out-of-band fragment from [htmx.OOB] or
+// [htmx.Response.AddOOB]. The existing element with the same ID is kept in place of the
+// placeholder. Don't add the placeholder as an out-of-band fragment of its own,
+// since that swaps the placeholder into the element instead of preserving it.
+//
+// Output:
+//
+//	<div id="player" hx-preserve></div>
+//
+// For more info, see https://htmx.org/attributes/hx-preserve/
+func Preserve(id string) template.HTML {
+	return template.HTML(`<div id="` + template.HTMLEscapeString(id) + `" hx-preserve></div>`)
+}
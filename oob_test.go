@@ -0,0 +1,260 @@
+package htmx
+
+import (
+	"context"
+	"html/template"
+	"testing"
+)
+
+func TestOOB(t *testing.T) {
+	testCases := []struct {
+		name   string
+		html   template.HTML
+		result template.HTML
+	}{
+		{
+			name:   "default swap",
+			html:   OOB("alerts", SwapDefault, "<p>Saved!</p>"),
+			result: `<div id="alerts" hx-swap-oob="outerHTML"><p>Saved!</p></div>`,
+		},
+		{
+			name:   "swap with modifier",
+			html:   OOB("alerts", SwapBeforeEnd.Transition(true), "<p>Saved!</p>"),
+			result: `<div id="alerts" hx-swap-oob="beforeend transition:true"><p>Saved!</p></div>`,
+		},
+		{
+			name:   "escaped id",
+			html:   OOB(`a"b`, SwapInnerHTML, ""),
+			result: `<div id="a&#34;b" hx-swap-oob="innerHTML"></div>`,
+		},
+	}
+
+	for _, tc := range testCases {
+		if tc.html != tc.result {
+			t.Errorf(`%s: got: "%v", want: "%v"`, tc.name, tc.html, tc.result)
+		}
+	}
+}
+
+func TestRenderHTMLOOB(t *testing.T) {
+	w := newMockResponseWriter()
+
+	_, err := NewResponse().RenderHTMLOOB(w, "<p>main</p>",
+		OOB("count", SwapInnerHTML, "3"),
+		OOB("alerts", SwapDefault, "<p>Saved!</p>"),
+	)
+	if err != nil {
+		t.Errorf("an error occurred writing HTML: %v", err)
+	}
+
+	want := `<p>main</p><div id="count" hx-swap-oob="innerHTML">3</div><div id="alerts" hx-swap-oob="outerHTML"><p>Saved!</p></div>`
+	if string(w.body) != want {
+		t.Errorf("wrong response body. got=%q, want=%q", string(w.body), want)
+	}
+
+	// Without OOB fragments it behaves like RenderHTML
+	w = newMockResponseWriter()
+	if _, err := NewResponse().RenderHTMLOOB(w, "<p>main</p>"); err != nil {
+		t.Errorf("an error occurred writing HTML: %v", err)
+	}
+	if string(w.body) != "<p>main</p>" {
+		t.Errorf("wrong response body. got=%q, want=%q", string(w.body), "<p>main</p>")
+	}
+}
+
+func TestAddOOB(t *testing.T) {
+	res := NewResponse().
+		AddOOB("alerts", SwapBeforeEnd, "<p>Saved!</p>").
+		AddOOB("count", SwapDefault, "3").
+		AddOOB("alerts", SwapBeforeEnd, "<p>Sent!</p>")
+
+	want := `<li>Joe</li>` +
+		`<div id="alerts" hx-swap-oob="beforeend"><p>Saved!</p></div>` +
+		`<div id="count" hx-swap-oob="outerHTML">3</div>` +
+		`<div id="alerts" hx-swap-oob="beforeend"><p>Sent!</p></div>`
+
+	w := newMockResponseWriter()
+	n, err := res.RenderHTML(w, "<li>Joe</li>")
+	if err != nil {
+		t.Errorf("an error occurred writing a response: %v", err)
+	}
+	if got := string(w.body); got != want {
+		t.Errorf(`RenderHTML: got: "%v", want: "%v"`, got, want)
+	}
+	if n != len(want) {
+		t.Errorf("wrong number of bytes written. got=%v, want=%v", n, len(want))
+	}
+
+	w = newMockResponseWriter()
+	err = res.RenderTempl(context.Background(), w, mockComponent("<li>Joe</li>"))
+	if err != nil {
+		t.Errorf("an error occurred writing a response: %v", err)
+	}
+	if got := string(w.body); got != want {
+		t.Errorf(`RenderTempl: got: "%v", want: "%v"`, got, want)
+	}
+}
+
+func TestFieldErrors(t *testing.T) {
+	testCases := []struct {
+		name   string
+		html   template.HTML
+		result template.HTML
+	}{
+		{
+			name: "sorted fields",
+			html: FieldErrors(map[string]template.HTML{
+				"password-error": "Too short",
+				"email-error":    "Email is taken",
+				"name-error":     "Required",
+			}),
+			result: `<div id="email-error" hx-swap-oob="innerHTML">Email is taken</div>` +
+				`<div id="name-error" hx-swap-oob="innerHTML">Required</div>` +
+				`<div id="password-error" hx-swap-oob="innerHTML">Too short</div>`,
+		},
+		{
+			name:   "empty map",
+			html:   FieldErrors(map[string]template.HTML{}),
+			result: "",
+		},
+		{
+			name:   "nil map",
+			html:   FieldErrors(nil),
+			result: "",
+		},
+	}
+
+	for _, tc := range testCases {
+		if tc.html != tc.result {
+			t.Errorf(`%s: got: "%v", want: "%v"`, tc.name, tc.html, tc.result)
+		}
+	}
+}
+
+func TestPreserve(t *testing.T) {
+	testCases := []struct {
+		name   string
+		html   template.HTML
+		result template.HTML
+	}{
+		{
+			name:   "id",
+			html:   Preserve("player"),
+			result: `<div id="player" hx-preserve></div>`,
+		},
+		{
+			name:   "escaped id",
+			html:   Preserve(`a"b`),
+			result: `<div id="a&#34;b" hx-preserve></div>`,
+		},
+		{
+			name:   "inside OOB",
+			html:   OOB("main", SwapInnerHTML, "<h1>Title</h1>"+Preserve("player")),
+			result: `<div id="main" hx-swap-oob="innerHTML"><h1>Title</h1><div id="player" hx-preserve></div></div>`,
+		},
+	}
+
+	for _, tc := range testCases {
+		if tc.html != tc.result {
+			t.Errorf(`%s: got: "%v", want: "%v"`, tc.name, tc.html, tc.result)
+		}
+	}
+}
+
+func TestAddOOB_Branches(t *testing.T) {
+	base := NewResponse().
+		AddOOB("a", SwapDefault, "A").
+		AddOOB("b", SwapDefault, "B").
+		AddOOB("c", SwapDefault, "C")
+
+	x := base.AddOOB("x", SwapDefault, "X")
+	y := base.AddOOB("y", SwapDefault, "Y")
+
+	for _, tc := range []struct {
+		name string
+		res  Response
+		want string
+	}{
+		{name: "x", res: x, want: "X"},
+		{name: "y", res: y, want: "Y"},
+	} {
+		w := newMockResponseWriter()
+		if _, err := tc.res.RenderHTML(w, ""); err != nil {
+			t.Errorf("%s: an error occurred writing a response: %v", tc.name, err)
+		}
+		want := OOB("a", SwapDefault, "A") + OOB("b", SwapDefault, "B") + OOB("c", SwapDefault, "C") +
+			OOB(tc.name, SwapDefault, template.HTML(tc.want))
+		if got := string(w.body); got != string(want) {
+			t.Errorf(`%s: got: "%v", want: "%v"`, tc.name, got, want)
+		}
+	}
+}
+
+func TestAddOOB_RenderMethods(t *testing.T) {
+	res := NewResponse().AddOOB("count", SwapDefault, "3")
+	oob := string(OOB("count", SwapDefault, "3"))
+
+	testCases := []struct {
+		name   string
+		render func(w *mockFlusher) error
+		want   string
+	}{
+		{
+			name: "RenderTemplCounted",
+			render: func(w *mockFlusher) error {
+				n, err := res.RenderTemplCounted(context.Background(), w, mockComponent("<li>Joe</li>"))
+				if n != len("<li>Joe</li>"+oob) {
+					t.Errorf("RenderTemplCounted: wrong number of bytes. got=%v, want=%v", n, len("<li>Joe</li>"+oob))
+				}
+				return err
+			},
+			want: "<li>Joe</li>" + oob,
+		},
+		{
+			name: "RenderTemplNotifyOnError",
+			render: func(w *mockFlusher) error {
+				return res.RenderTemplNotifyOnError(context.Background(), w, mockComponent("<li>Joe</li>"), "renderError")
+			},
+			want: "<li>Joe</li>" + oob,
+		},
+		{
+			name: "RenderTemplNotifyOnError failure",
+			render: func(w *mockFlusher) error {
+				_ = res.RenderTemplNotifyOnError(context.Background(), w, failingComponent{}, "renderError")
+				return nil
+			},
+			want: "",
+		},
+		{
+			name: "StreamHTML",
+			render: func(w *mockFlusher) error {
+				s, err := res.StreamHTML(w)
+				if err != nil {
+					return err
+				}
+				if err := s.Write("<li>Joe</li>"); err != nil {
+					return err
+				}
+				return s.Close()
+			},
+			want: "<li>Joe</li>" + oob,
+		},
+		{
+			name: "WriteJSON",
+			render: func(w *mockFlusher) error {
+				return res.WriteJSON(w, "Joe")
+			},
+			want: `"Joe"`,
+		},
+	}
+
+	for _, tc := range testCases {
+		w := &mockFlusher{mockResponseWriter: newMockResponseWriter()}
+		if err := tc.render(w); err != nil {
+			t.Errorf("%s: an error occurred writing a response: %v", tc.name, err)
+		}
+		if got := string(w.body); got != tc.want {
+			t.Errorf(`%s: got: "%v", want: "%v"`, tc.name, got, tc.want)
+		}
+	}
+}
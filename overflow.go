@@ -0,0 +1,121 @@
+package htmx
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+// MaxHeaderBytes is the maximum length in bytes of the header values of a response,
+// such as 'HX-Trigger' or 'HX-Location'.
+// Proxies often reject or silently truncate headers over a limit (commonly 8KB).
+//
+// Trigger header values over the limit are handled according to the policy set with
+// [htmx.SetTriggerOverflowPolicy]. Other header values over the limit make
+// [htmx.Response.Headers] and [htmx.Response.Write] return an error.
+// If zero or negative, there is no limit, which is the default.
+var MaxHeaderBytes = 0
+
+// TriggerDetailTruncated replaces the details of triggers that don't fit in
+// [htmx.MaxHeaderBytes] under the [htmx.PolicyTruncateDetail] and [htmx.PolicyDropDetail] policies.
+const TriggerDetailTruncated = "[truncated]"
+
+// TriggerOverflowPolicy determines what happens when a trigger header value
+// is longer than [htmx.MaxHeaderBytes].
+type TriggerOverflowPolicy int
+
+const (
+	// Return an error from [htmx.Response.Headers] and [htmx.Response.Write].
+	//
+	// This is the default policy.
+	PolicyError TriggerOverflowPolicy = iota
+
+	// Keep trigger details in order as long as they fit, and replace the details
+	// that don't with [htmx.TriggerDetailTruncated].
+	PolicyTruncateDetail
+
+	// Replace the details of all triggers with [htmx.TriggerDetailTruncated].
+	PolicyDropDetail
+)
+
+// The policy set with SetTriggerOverflowPolicy
+var triggerOverflowPolicy atomic.Int64
+
+// SetTriggerOverflowPolicy sets what happens when a trigger header value
+// is longer than [htmx.MaxHeaderBytes].
+//
+// With [htmx.PolicyTruncateDetail] and [htmx.PolicyDropDetail], every event still fires,
+// but oversized details are replaced. If the header is still too long without details,
+// an error is returned.
+func SetTriggerOverflowPolicy(policy TriggerOverflowPolicy) {
+	triggerOverflowPolicy.Store(int64(policy))
+}
+
+// fitTriggers checks that the header value of the given triggers fits in MaxHeaderBytes,
+// applying the overflow policy if it doesn't.
+func fitTriggers(header string, triggers []EventTrigger, value string) (string, error) {
+	if MaxHeaderBytes <= 0 || len(value) <= MaxHeaderBytes {
+		return value, nil
+	}
+
+	policy := TriggerOverflowPolicy(triggerOverflowPolicy.Load())
+	if policy == PolicyError {
+		return "", overflowError(header, len(value))
+	}
+
+	// Start with all details replaced, which is the smallest the value can get
+	fitted := make([]EventTrigger, len(triggers))
+	for i, t := range triggers {
+		fitted[i] = truncateDetail(t)
+	}
+
+	value, err := triggersToString(fitted)
+	if err != nil {
+		return "", err
+	}
+	if len(value) > MaxHeaderBytes {
+		return "", overflowError(header, len(value))
+	}
+
+	if policy == PolicyTruncateDetail {
+		// Restore details in order for as long as they fit
+		for i, t := range triggers {
+			fitted[i] = t
+
+			restored, err := triggersToString(fitted)
+			if err != nil {
+				return "", err
+			}
+
+			if len(restored) > MaxHeaderBytes {
+				fitted[i] = truncateDetail(t)
+				continue
+			}
+			value = restored
+		}
+	}
+
+	return value, nil
+}
+
+// truncateDetail replaces the detail of a trigger with TriggerDetailTruncated.
+// Plain triggers have no detail, so they are returned unchanged.
+func truncateDetail(t EventTrigger) EventTrigger {
+	if _, ok := t.(triggerPlain); ok {
+		return t
+	}
+	eventName, _ := triggerParts(t)
+	return TriggerDetail(eventName, TriggerDetailTruncated)
+}
+
+// checkHeaderSize returns an error if a header value is longer than MaxHeaderBytes.
+func checkHeaderSize(header string, value string) error {
+	if MaxHeaderBytes <= 0 || len(value) <= MaxHeaderBytes {
+		return nil
+	}
+	return overflowError(header, len(value))
+}
+
+func overflowError(header string, length int) error {
+	return fmt.Errorf("'%s' header value is %d bytes, which exceeds MaxHeaderBytes (%d bytes)",
+		header, length, MaxHeaderBytes)
+}
@@ -0,0 +1,96 @@
+package htmx
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTriggerOverflowPolicy(t *testing.T) {
+	defer func(limit int) {
+		MaxHeaderBytes = limit
+		SetTriggerOverflowPolicy(PolicyError)
+	}(MaxHeaderBytes)
+	MaxHeaderBytes = 80
+
+	response := NewResponse().AddTrigger(
+		TriggerDetail("small", "hi"),
+		TriggerDetail("big", strings.Repeat("x", 100)),
+		Trigger("plain"),
+		TriggerObject("medium", map[string]string{"a": "b"}),
+	)
+
+	testCases := []struct {
+		name    string
+		policy  TriggerOverflowPolicy
+		result  string
+		wantErr bool
+	}{
+		{
+			name:    "error",
+			policy:  PolicyError,
+			wantErr: true,
+		},
+		{
+			name:   "truncate detail",
+			policy: PolicyTruncateDetail,
+			result: `{"small":"hi","big":"[truncated]","plain":"","medium":{"a":"b"}}`,
+		},
+		{
+			name:   "drop detail",
+			policy: PolicyDropDetail,
+			result: `{"small":"[truncated]","big":"[truncated]","plain":"","medium":"[truncated]"}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		SetTriggerOverflowPolicy(tc.policy)
+
+		headers, err := response.Headers()
+		if (err != nil) != tc.wantErr {
+			t.Errorf("%s: got error %v, want error: %v", tc.name, err, tc.wantErr)
+		}
+		if got := headers[HeaderTrigger]; got != tc.result {
+			t.Errorf(`%s: got: "%v", want: "%v"`, tc.name, got, tc.result)
+		}
+	}
+
+	// Errors if the events don't fit even without details
+	SetTriggerOverflowPolicy(PolicyDropDetail)
+	_, err := NewResponse().AddTrigger(TriggerDetail(strings.Repeat("e", 100), "x")).Headers()
+	if err == nil {
+		t.Errorf("expected an error when event names alone exceed the limit")
+	}
+}
+
+func TestMaxHeaderBytes(t *testing.T) {
+	defer func(limit int) { MaxHeaderBytes = limit }(MaxHeaderBytes)
+
+	response := NewResponse().
+		AddTrigger(TriggerDetail("showMessage", strings.Repeat("x", 100))).
+		Retarget("#main")
+
+	MaxHeaderBytes = 0
+	if _, err := response.Headers(); err != nil {
+		t.Errorf("expected no limit by default, got error %v", err)
+	}
+
+	MaxHeaderBytes = 64
+	_, err := response.Headers()
+	if err == nil {
+		t.Fatalf("expected an error for a trigger header over MaxHeaderBytes")
+	}
+	if !strings.Contains(err.Error(), "'HX-Trigger'") || !strings.Contains(err.Error(), "(64 bytes)") {
+		t.Errorf("error should name the header and the limit, got %q", err)
+	}
+
+	_, err = NewResponse().
+		LocationWithContext("/contacts", LocationContext{Target: strings.Repeat("#a", 50)}).
+		Headers()
+	if err == nil || !strings.Contains(err.Error(), "'HX-Location'") {
+		t.Errorf("expected an error naming 'HX-Location', got %v", err)
+	}
+
+	if err := NewResponse().Retarget("#main").Write(newMockResponseWriter()); err != nil {
+		t.Errorf("headers within MaxHeaderBytes should be written, got error %v", err)
+	}
+}
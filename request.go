@@ -1,7 +1,13 @@
 package htmx
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
 )
 
 // IsHTMX returns true if the given request
@@ -11,7 +17,17 @@ import (
 //
 // Checks if header 'HX-Request' is 'true'.
 func IsHTMX(r *http.Request) bool {
-	return r.Header.Get(HeaderRequest) == "true"
+	return IsHTMXHeader(r.Header)
+}
+
+// IsHTMXHeader returns true if the given request headers
+// are from a request made by HTMX.
+//
+// This is the same as [htmx.IsHTMX], for when only the headers are available.
+//
+// Checks if header 'HX-Request' is 'true'.
+func IsHTMXHeader(h http.Header) bool {
+	return h.Get(HeaderRequest) == "true"
 }
 
 // IsBoosted returns true if the given request
@@ -23,7 +39,19 @@ func IsHTMX(r *http.Request) bool {
 //
 // For more info, see https://htmx.org/attributes/hx-boost/
 func IsBoosted(r *http.Request) bool {
-	return r.Header.Get(HeaderBoosted) == "true"
+	return IsBoostedHeader(r.Header)
+}
+
+// IsBoostedHeader returns true if the given request headers
+// are from a request made via an element using 'hx-boost'.
+//
+// This is the same as [htmx.IsBoosted], for when only the headers are available.
+//
+// Checks if header 'HX-Boosted' is 'true'.
+//
+// For more info, see https://htmx.org/attributes/hx-boost/
+func IsBoostedHeader(h http.Header) bool {
+	return h.Get(HeaderBoosted) == "true"
 }
 
 // IsHistoryRestoreRequest returns true if the given request
@@ -31,7 +59,40 @@ func IsBoosted(r *http.Request) bool {
 //
 // Checks if header 'HX-History-Restore-Request' is 'true'.
 func IsHistoryRestoreRequest(r *http.Request) bool {
-	return r.Header.Get(HeaderHistoryRestoreRequest) == "true"
+	return IsHistoryRestoreRequestHeader(r.Header)
+}
+
+// IsHistoryRestoreRequestHeader returns true if the given request headers
+// are from a request for history restoration after a miss in the local history cache.
+//
+// This is the same as [htmx.IsHistoryRestoreRequest], for when only the headers are available.
+//
+// Checks if header 'HX-History-Restore-Request' is 'true'.
+func IsHistoryRestoreRequestHeader(h http.Header) bool {
+	return h.Get(HeaderHistoryRestoreRequest) == "true"
+}
+
+// ShouldRenderFullPage returns true if a full page should be rendered for the given request,
+// instead of a fragment.
+//
+// This is the case for requests not made by HTMX, like the initial page load or a page refresh,
+// which need the whole document. Requests made via an element using 'hx-boost' also return true,
+// since boosted links and forms swap the whole '<body>' with the response, like a normal page navigation.
+// Other HTMX requests target a specific element, so a fragment is enough.
+//
+// Use [htmx.ShouldRenderFullPageOpt] to render fragments for boosted requests.
+//
+// For more info, see https://htmx.org/attributes/hx-boost/
+func ShouldRenderFullPage(r *http.Request) bool {
+	return ShouldRenderFullPageOpt(r, true)
+}
+
+// ShouldRenderFullPageOpt is the same as [htmx.ShouldRenderFullPage], but boosted
+// requests only return true if boostedAsFull is true.
+//
+// For more info, see https://htmx.org/attributes/hx-boost/
+func ShouldRenderFullPageOpt(r *http.Request, boostedAsFull bool) bool {
+	return !IsHTMX(r) || (boostedAsFull && IsBoosted(r))
 }
 
 // GetCurrentURL returns the current URL that HTMX made this request from.
@@ -44,6 +105,22 @@ func GetCurrentURL(r *http.Request) (string, bool) {
 	return r.Header.Get(HeaderCurrentURL), true
 }
 
+// GetCurrentURLParsed returns the parsed current URL that HTMX made this request from.
+//
+// Returns false if header 'HX-Current-URL' does not exist or is not a valid URL.
+func GetCurrentURLParsed(r *http.Request) (*url.URL, bool) {
+	value, ok := GetCurrentURL(r)
+	if !ok {
+		return nil, false
+	}
+
+	u, err := url.Parse(value)
+	if err != nil {
+		return nil, false
+	}
+	return u, true
+}
+
 // GetPrompt returns the user response to an hx-prompt from a given request.
 //
 // Returns false if header 'HX-Prompt' does not exist.
@@ -56,6 +133,26 @@ func GetPrompt(r *http.Request) (string, bool) {
 	return r.Header.Get(HeaderPrompt), true
 }
 
+// GetPromptJSON unmarshals the JSON user response to an hx-prompt from a given request into v.
+//
+// HTMX sends the prompt response as plain text, so this is only for apps whose
+// prompts produce JSON, e.g. with a custom prompt dialog. Use [htmx.GetPrompt] otherwise.
+//
+// Returns an error if header 'HX-Prompt' does not exist or is not valid JSON.
+//
+// For more info, see https://htmx.org/attributes/hx-prompt/
+func GetPromptJSON(r *http.Request, v any) error {
+	prompt, ok := GetPrompt(r)
+	if !ok {
+		return errors.New("'HX-Prompt' header does not exist")
+	}
+
+	if err := json.Unmarshal([]byte(prompt), v); err != nil {
+		return fmt.Errorf("unmarshalling 'HX-Prompt' failed: %w", err)
+	}
+	return nil
+}
+
 // GetTarget returns the ID of the target element if it exists from a given request.
 //
 // Returns false if header 'HX-Target' does not exist.
@@ -68,6 +165,19 @@ func GetTarget(r *http.Request) (string, bool) {
 	return r.Header.Get(HeaderTarget), true
 }
 
+// TargetIs returns true if the ID of the target element from a given request is id.
+//
+// A leading '#' in id is ignored, so both "contacts" and "#contacts" match a target
+// with the ID 'contacts'.
+//
+// Returns false if header 'HX-Target' does not exist.
+//
+// For more info, see https://htmx.org/attributes/hx-target/
+func TargetIs(r *http.Request, id string) bool {
+	target, ok := GetTarget(r)
+	return ok && target == strings.TrimPrefix(id, "#")
+}
+
 // GetTriggerName returns the 'name' of the triggered element if it exists from a given request.
 //
 // Returns false if header 'HX-Trigger-Name' does not exist.
@@ -80,6 +190,17 @@ func GetTriggerName(r *http.Request) (string, bool) {
 	return r.Header.Get(HeaderTriggerName), true
 }
 
+// TriggeredByName returns true if the 'name' of the triggered element from a given request is name,
+// e.g. to tell which submit button of a form with several buttons was pressed.
+//
+// Returns false if header 'HX-Trigger-Name' does not exist.
+//
+// For more info, see https://htmx.org/attributes/hx-trigger/
+func TriggeredByName(r *http.Request, name string) bool {
+	triggerName, ok := GetTriggerName(r)
+	return ok && triggerName == name
+}
+
 // GetTrigger returns the ID of the triggered element if it exists from a given request.
 //
 // Returns false if header 'HX-Trigger' does not exist.
@@ -91,3 +212,205 @@ func GetTrigger(r *http.Request) (string, bool) {
 	}
 	return r.Header.Get(HeaderTrigger), true
 }
+
+// GetTriggerID returns the ID of the triggered element if it exists from a given request.
+//
+// This is the same as [htmx.GetTrigger], named after what the header contains.
+//
+// Returns false if header 'HX-Trigger' does not exist.
+//
+// For more info, see https://htmx.org/attributes/hx-trigger/
+func GetTriggerID(r *http.Request) (string, bool) {
+	return GetTrigger(r)
+}
+
+// GetTriggeringEvent returns the JSON representation of the event that triggered the request.
+//
+// HTMX does not send this by default; it is sent by the 'event-header' extension
+// in the 'Triggering-Event' header. The value is returned as-is.
+//
+// Returns false if header 'Triggering-Event' does not exist.
+//
+// For more info, see https://github.com/bigskysoftware/htmx-extensions/blob/main/src/event-header/README.md
+func GetTriggeringEvent(r *http.Request) (string, bool) {
+	if _, ok := r.Header[http.CanonicalHeaderKey(HeaderTriggeringEvent)]; !ok {
+		return "", false
+	}
+	return r.Header.Get(HeaderTriggeringEvent), true
+}
+
+// GetVersion returns the HTMX version from a request header set by the application.
+//
+// HTMX does not send its version by default, so this relies on the application
+// adding the header to HTMX requests, e.g. with 'hx-headers' or the 'htmx:configRequest' event.
+//
+// Returns false if the header does not exist.
+func GetVersion(r *http.Request, headerName string) (string, bool) {
+	if _, ok := r.Header[http.CanonicalHeaderKey(headerName)]; !ok {
+		return "", false
+	}
+	return r.Header.Get(headerName), true
+}
+
+// IsHTMXVersionAtLeast returns true if the HTMX version from a request header set by the
+// application is at least the given minimum version, such as "1.9.10" or "v2.0".
+//
+// Versions are compared by their major, minor and patch numbers; pre-release and build
+// suffixes are ignored. Returns false if the header does not exist or either version is invalid.
+//
+// See [htmx.GetVersion] for how the header is sent.
+func IsHTMXVersionAtLeast(r *http.Request, headerName string, min string) bool {
+	version, ok := GetVersion(r, headerName)
+	if !ok {
+		return false
+	}
+
+	v, ok := parseVersion(version)
+	if !ok {
+		return false
+	}
+	m, ok := parseVersion(min)
+	if !ok {
+		return false
+	}
+
+	for i := range v {
+		if v[i] != m[i] {
+			return v[i] > m[i]
+		}
+	}
+	return true
+}
+
+// parseVersion parses a version like "v1.9.10" into its major, minor and patch numbers.
+// Missing numbers are zero.
+func parseVersion(version string) ([3]int, bool) {
+	var v [3]int
+
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+	version, _, _ = strings.Cut(version, "+")
+	version, _, _ = strings.Cut(version, "-")
+
+	parts := strings.Split(version, ".")
+	if len(parts) > len(v) {
+		return v, false
+	}
+
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return v, false
+		}
+		v[i] = n
+	}
+
+	return v, true
+}
+
+// orFallback returns value if ok and value is not empty, otherwise fallback.
+func orFallback(value string, ok bool, fallback string) string {
+	if !ok || value == "" {
+		return fallback
+	}
+	return value
+}
+
+// GetCurrentURLOr returns the current URL that HTMX made this request from,
+// or fallback if header 'HX-Current-URL' does not exist or is empty.
+func GetCurrentURLOr(r *http.Request, fallback string) string {
+	value, ok := GetCurrentURL(r)
+	return orFallback(value, ok, fallback)
+}
+
+// GetPromptOr returns the user response to an hx-prompt from a given request,
+// or fallback if header 'HX-Prompt' does not exist or is empty.
+//
+// For more info, see https://htmx.org/attributes/hx-prompt/
+func GetPromptOr(r *http.Request, fallback string) string {
+	value, ok := GetPrompt(r)
+	return orFallback(value, ok, fallback)
+}
+
+// GetTargetOr returns the ID of the target element from a given request,
+// or fallback if header 'HX-Target' does not exist or is empty.
+//
+// For more info, see https://htmx.org/attributes/hx-target/
+func GetTargetOr(r *http.Request, fallback string) string {
+	value, ok := GetTarget(r)
+	return orFallback(value, ok, fallback)
+}
+
+// GetTriggerNameOr returns the 'name' of the triggered element from a given request,
+// or fallback if header 'HX-Trigger-Name' does not exist or is empty.
+//
+// For more info, see https://htmx.org/attributes/hx-trigger/
+func GetTriggerNameOr(r *http.Request, fallback string) string {
+	value, ok := GetTriggerName(r)
+	return orFallback(value, ok, fallback)
+}
+
+// GetTriggerOr returns the ID of the triggered element from a given request,
+// or fallback if header 'HX-Trigger' does not exist or is empty.
+//
+// For more info, see https://htmx.org/attributes/hx-trigger/
+func GetTriggerOr(r *http.Request, fallback string) string {
+	value, ok := GetTrigger(r)
+	return orFallback(value, ok, fallback)
+}
+
+// RequestSnapshot contains all the HTMX request headers of a request,
+// read in one pass by [htmx.Snapshot].
+//
+// Fields of headers that do not exist are zero-valued, with the accompanying
+// '*Present' field set to false.
+type RequestSnapshot struct {
+	// Whether the request was made by HTMX.
+	IsHTMX bool
+	// Whether the request was made via an element using 'hx-boost'.
+	IsBoosted bool
+	// Whether the request is for history restoration after a miss in the local history cache.
+	IsHistoryRestore bool
+
+	// The current URL of the browser.
+	CurrentURL        string
+	CurrentURLPresent bool
+	// The user response to an hx-prompt.
+	Prompt        string
+	PromptPresent bool
+	// The ID of the target element.
+	Target        string
+	TargetPresent bool
+	// The 'name' of the triggered element.
+	TriggerName        string
+	TriggerNamePresent bool
+	// The ID of the triggered element.
+	TriggerID        string
+	TriggerIDPresent bool
+}
+
+// Snapshot returns all the HTMX request headers of a given request as a [htmx.RequestSnapshot].
+//
+// This is useful for logging the HTMX details of a request as a single value.
+func Snapshot(r *http.Request) RequestSnapshot {
+	var s RequestSnapshot
+
+	s.IsHTMX = IsHTMXHeader(r.Header)
+	s.IsBoosted = IsBoostedHeader(r.Header)
+	s.IsHistoryRestore = IsHistoryRestoreRequestHeader(r.Header)
+
+	s.CurrentURL, s.CurrentURLPresent = lookupHeader(r, HeaderCurrentURL)
+	s.Prompt, s.PromptPresent = lookupHeader(r, HeaderPrompt)
+	s.Target, s.TargetPresent = lookupHeader(r, HeaderTarget)
+	s.TriggerName, s.TriggerNamePresent = lookupHeader(r, HeaderTriggerName)
+	s.TriggerID, s.TriggerIDPresent = lookupHeader(r, HeaderTrigger)
+
+	return s
+}
+
+func lookupHeader(r *http.Request, key string) (string, bool) {
+	values, ok := r.Header[http.CanonicalHeaderKey(key)]
+	if !ok || len(values) == 0 {
+		return "", false
+	}
+	return values[0], true
+}
@@ -0,0 +1,326 @@
+package htmx
+
+import (
+	"net/http"
+	"testing"
+)
+
+func newHTMXRequest(headers map[string]string) *http.Request {
+	r := &http.Request{Header: http.Header{}}
+	for k, v := range headers {
+		r.Header.Set(k, v)
+	}
+	return r
+}
+
+func TestGetTriggerID(t *testing.T) {
+	r := newHTMXRequest(map[string]string{
+		HeaderRequest: "true",
+		HeaderTrigger: "save-button",
+	})
+
+	if got, ok := GetTriggerID(r); !ok || got != "save-button" {
+		t.Errorf("wrong trigger ID. got=%q, %v, want=%q, %v", got, ok, "save-button", true)
+	}
+
+	if _, ok := GetTriggerID(newHTMXRequest(nil)); ok {
+		t.Errorf("expected no trigger ID")
+	}
+}
+
+func TestGetTriggeringEvent(t *testing.T) {
+	event := `{"type":"click","target":"button#save-button","isTrusted":true}`
+
+	r := newHTMXRequest(map[string]string{
+		HeaderRequest:         "true",
+		HeaderTriggeringEvent: event,
+	})
+
+	if got, ok := GetTriggeringEvent(r); !ok || got != event {
+		t.Errorf("wrong triggering event. got=%q, %v, want=%q, %v", got, ok, event, true)
+	}
+
+	if _, ok := GetTriggeringEvent(newHTMXRequest(map[string]string{HeaderRequest: "true"})); ok {
+		t.Errorf("expected no triggering event")
+	}
+}
+
+func TestIsHTMXVersionAtLeast(t *testing.T) {
+	const header = "X-HTMX-Version"
+
+	testCases := []struct {
+		name    string
+		version string
+		min     string
+		result  bool
+	}{
+		{name: "equal", version: "1.9.10", min: "1.9.10", result: true},
+		{name: "newer patch", version: "1.9.10", min: "1.9.2", result: true},
+		{name: "older minor", version: "1.8.6", min: "1.9", result: false},
+		{name: "newer major with prefix", version: "v2.0.0", min: "1.9.10", result: true},
+		{name: "pre-release suffix", version: "2.0.0-beta1", min: "2", result: true},
+		{name: "invalid version", version: "latest", min: "1.0", result: false},
+		{name: "invalid minimum", version: "1.9.10", min: "1.x", result: false},
+	}
+
+	for _, tc := range testCases {
+		r := newHTMXRequest(map[string]string{header: tc.version})
+		if result := IsHTMXVersionAtLeast(r, header, tc.min); result != tc.result {
+			t.Errorf(`%s: got: "%v", want: "%v"`, tc.name, result, tc.result)
+		}
+	}
+
+	if IsHTMXVersionAtLeast(newHTMXRequest(nil), header, "1.0") {
+		t.Errorf("expected false without a version header")
+	}
+	if _, ok := GetVersion(newHTMXRequest(nil), header); ok {
+		t.Errorf("expected no version without a version header")
+	}
+}
+
+func TestGetOr(t *testing.T) {
+	r := newHTMXRequest(map[string]string{
+		HeaderCurrentURL:  "https://example.com/",
+		HeaderPrompt:      "yes",
+		HeaderTarget:      "",
+		HeaderTriggerName: "save",
+		HeaderTrigger:     "save-button",
+	})
+	empty := newHTMXRequest(nil)
+
+	testCases := []struct {
+		name   string
+		getter func(*http.Request, string) string
+		result string
+	}{
+		{name: "current URL", getter: GetCurrentURLOr, result: "https://example.com/"},
+		{name: "prompt", getter: GetPromptOr, result: "yes"},
+		{name: "empty target", getter: GetTargetOr, result: "fallback"},
+		{name: "trigger name", getter: GetTriggerNameOr, result: "save"},
+		{name: "trigger", getter: GetTriggerOr, result: "save-button"},
+	}
+
+	for _, tc := range testCases {
+		if result := tc.getter(r, "fallback"); result != tc.result {
+			t.Errorf(`%s: got: "%v", want: "%v"`, tc.name, result, tc.result)
+		}
+		if result := tc.getter(empty, "fallback"); result != "fallback" {
+			t.Errorf(`%s without header: got: "%v", want: "%v"`, tc.name, result, "fallback")
+		}
+	}
+}
+
+func TestGetCurrentURLParsed(t *testing.T) {
+	r := newHTMXRequest(map[string]string{
+		HeaderCurrentURL: "https://example.com/contacts?page=2&q=joe",
+	})
+
+	u, ok := GetCurrentURLParsed(r)
+	if !ok {
+		t.Fatalf("expected a parsed URL")
+	}
+	if u.Path != "/contacts" {
+		t.Errorf("wrong path. got=%q, want=%q", u.Path, "/contacts")
+	}
+	if got := u.Query().Get("q"); got != "joe" {
+		t.Errorf("wrong query value. got=%q, want=%q", got, "joe")
+	}
+
+	if _, ok := GetCurrentURLParsed(newHTMXRequest(nil)); ok {
+		t.Errorf("expected no URL without the header")
+	}
+
+	invalid := newHTMXRequest(map[string]string{HeaderCurrentURL: "http://[::1"})
+	if u, ok := GetCurrentURLParsed(invalid); ok || u != nil {
+		t.Errorf("expected no URL for an invalid header. got=%v, %v", u, ok)
+	}
+}
+
+func TestSnapshot(t *testing.T) {
+	r := newHTMXRequest(map[string]string{
+		HeaderRequest:               "true",
+		HeaderBoosted:               "true",
+		HeaderHistoryRestoreRequest: "true",
+		HeaderCurrentURL:            "https://example.com/contacts",
+		HeaderPrompt:                "Yes",
+		HeaderTarget:                "contact-list",
+		HeaderTriggerName:           "delete",
+		HeaderTrigger:               "delete-button",
+	})
+
+	want := RequestSnapshot{
+		IsHTMX:             true,
+		IsBoosted:          true,
+		IsHistoryRestore:   true,
+		CurrentURL:         "https://example.com/contacts",
+		CurrentURLPresent:  true,
+		Prompt:             "Yes",
+		PromptPresent:      true,
+		Target:             "contact-list",
+		TargetPresent:      true,
+		TriggerName:        "delete",
+		TriggerNamePresent: true,
+		TriggerID:          "delete-button",
+		TriggerIDPresent:   true,
+	}
+
+	if got := Snapshot(r); got != want {
+		t.Errorf("wrong snapshot. got=%+v, want=%+v", got, want)
+	}
+
+	if got := Snapshot(newHTMXRequest(nil)); got != (RequestSnapshot{}) {
+		t.Errorf("wrong snapshot for a non-HTMX request. got=%+v, want=%+v", got, RequestSnapshot{})
+	}
+}
+
+func TestIsHTMXHeader(t *testing.T) {
+	h := http.Header{}
+	h.Set(HeaderRequest, "true")
+	h.Set(HeaderBoosted, "true")
+	h.Set(HeaderHistoryRestoreRequest, "true")
+
+	testCases := []struct {
+		name   string
+		result bool
+		want   bool
+	}{
+		{name: "IsHTMXHeader", result: IsHTMXHeader(h), want: true},
+		{name: "IsBoostedHeader", result: IsBoostedHeader(h), want: true},
+		{name: "IsHistoryRestoreRequestHeader", result: IsHistoryRestoreRequestHeader(h), want: true},
+		{name: "IsHTMXHeader empty", result: IsHTMXHeader(http.Header{}), want: false},
+		{name: "IsBoostedHeader empty", result: IsBoostedHeader(http.Header{}), want: false},
+		{name: "IsHistoryRestoreRequestHeader empty", result: IsHistoryRestoreRequestHeader(http.Header{}), want: false},
+		{name: "IsHTMX", result: IsHTMX(&http.Request{Header: h}), want: true},
+	}
+
+	for _, tc := range testCases {
+		if tc.result != tc.want {
+			t.Errorf(`%s: got: "%v", want: "%v"`, tc.name, tc.result, tc.want)
+		}
+	}
+}
+
+func TestTargetIs(t *testing.T) {
+	r := newHTMXRequest(map[string]string{
+		HeaderRequest: "true",
+		HeaderTarget:  "contacts",
+	})
+
+	testCases := []struct {
+		name   string
+		result bool
+		want   bool
+	}{
+		{name: "match", result: TargetIs(r, "contacts"), want: true},
+		{name: "match with #", result: TargetIs(r, "#contacts"), want: true},
+		{name: "different target", result: TargetIs(r, "errors"), want: false},
+		{name: "absent header", result: TargetIs(newHTMXRequest(nil), "contacts"), want: false},
+		{name: "absent header with empty id", result: TargetIs(newHTMXRequest(nil), ""), want: false},
+	}
+
+	for _, tc := range testCases {
+		if tc.result != tc.want {
+			t.Errorf(`%s: got: "%v", want: "%v"`, tc.name, tc.result, tc.want)
+		}
+	}
+}
+
+func TestShouldRenderFullPage(t *testing.T) {
+	nonHTMX := newHTMXRequest(nil)
+	htmxRequest := newHTMXRequest(map[string]string{HeaderRequest: "true"})
+	boosted := newHTMXRequest(map[string]string{HeaderRequest: "true", HeaderBoosted: "true"})
+
+	testCases := []struct {
+		name string
+		req  *http.Request
+		want bool
+	}{
+		{name: "non-HTMX", req: nonHTMX, want: true},
+		{name: "plain HTMX", req: htmxRequest, want: false},
+		{name: "boosted", req: boosted, want: true},
+	}
+
+	for _, tc := range testCases {
+		if got := ShouldRenderFullPage(tc.req); got != tc.want {
+			t.Errorf(`%s: got: "%v", want: "%v"`, tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestShouldRenderFullPageOpt(t *testing.T) {
+	nonHTMX := newHTMXRequest(nil)
+	htmxRequest := newHTMXRequest(map[string]string{HeaderRequest: "true"})
+	boosted := newHTMXRequest(map[string]string{HeaderRequest: "true", HeaderBoosted: "true"})
+
+	testCases := []struct {
+		name          string
+		req           *http.Request
+		boostedAsFull bool
+		want          bool
+	}{
+		{name: "boosted as full page", req: boosted, boostedAsFull: true, want: true},
+		{name: "boosted as fragment", req: boosted, boostedAsFull: false, want: false},
+		{name: "non-HTMX with boosted as fragment", req: nonHTMX, boostedAsFull: false, want: true},
+		{name: "plain HTMX with boosted as fragment", req: htmxRequest, boostedAsFull: false, want: false},
+	}
+
+	for _, tc := range testCases {
+		if got := ShouldRenderFullPageOpt(tc.req, tc.boostedAsFull); got != tc.want {
+			t.Errorf(`%s: got: "%v", want: "%v"`, tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestTriggeredByName(t *testing.T) {
+	r := newHTMXRequest(map[string]string{
+		HeaderRequest:     "true",
+		HeaderTriggerName: "archive",
+	})
+
+	testCases := []struct {
+		name   string
+		result bool
+		want   bool
+	}{
+		{name: "match", result: TriggeredByName(r, "archive"), want: true},
+		{name: "no match", result: TriggeredByName(r, "delete"), want: false},
+		{name: "absent header", result: TriggeredByName(newHTMXRequest(nil), "archive"), want: false},
+		{name: "absent header with empty name", result: TriggeredByName(newHTMXRequest(nil), ""), want: false},
+		{name: "GetTriggerNameOr", result: GetTriggerNameOr(newHTMXRequest(nil), "save") == "save", want: true},
+	}
+
+	for _, tc := range testCases {
+		if tc.result != tc.want {
+			t.Errorf(`%s: got: "%v", want: "%v"`, tc.name, tc.result, tc.want)
+		}
+	}
+}
+
+func TestGetPromptJSON(t *testing.T) {
+	type prompt struct {
+		Name  string `json:"name"`
+		Count int    `json:"count"`
+	}
+
+	r := newHTMXRequest(map[string]string{
+		HeaderRequest: "true",
+		HeaderPrompt:  `{"name":"Joe","count":3}`,
+	})
+
+	var got prompt
+	if err := GetPromptJSON(r, &got); err != nil {
+		t.Errorf("an error occurred unmarshalling the prompt: %v", err)
+	}
+	if want := (prompt{Name: "Joe", Count: 3}); got != want {
+		t.Errorf("wrong prompt. got=%+v, want=%+v", got, want)
+	}
+
+	if err := GetPromptJSON(newHTMXRequest(nil), &got); err == nil {
+		t.Errorf("expected an error for a missing 'HX-Prompt' header")
+	}
+
+	invalid := newHTMXRequest(map[string]string{HeaderPrompt: "yes"})
+	if err := GetPromptJSON(invalid, &got); err == nil {
+		t.Errorf("expected an error for a plain text 'HX-Prompt' header")
+	}
+}
@@ -1,8 +1,14 @@
 package htmx
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
+	"mime"
+	"net/http"
+	"net/url"
 	"strings"
+	"time"
 )
 
 const (
@@ -10,6 +16,44 @@ const (
 	falseString = "false"
 )
 
+// Header sets an arbitrary header, such as 'Cache-Control' or a custom 'X-' header,
+// to be written along with the HTMX headers.
+//
+// Calling Header again with the same key overwrites the previous value.
+// The header name and value are not validated.
+func (r Response) Header(key string, value string) Response {
+	r.headers[key] = value
+	return r
+}
+
+// Cookie adds a cookie to be set with [http.SetCookie] along with the HTMX headers,
+// e.g. a session cookie set in the same response as [htmx.Response.Redirect].
+//
+// This can be called multiple times; all cookies are written, in the order they were added.
+// If c is nil, the response is unchanged.
+func (r Response) Cookie(c *http.Cookie) Response {
+	if c == nil {
+		return r
+	}
+	r.cookies = append(r.cookies[:len(r.cookies):len(r.cookies)], c)
+	return r
+}
+
+// ContentType sets the 'Content-Type' header.
+func (r Response) ContentType(value string) Response {
+	return r.Header("Content-Type", value)
+}
+
+// ContentTypeHTML sets the 'Content-Type' header to 'text/html; charset=utf-8'.
+func (r Response) ContentTypeHTML() Response {
+	return r.ContentType("text/html; charset=utf-8")
+}
+
+// ContentTypeJSON sets the 'Content-Type' header to 'application/json'.
+func (r Response) ContentTypeJSON() Response {
+	return r.ContentType("application/json")
+}
+
 // StatusCode sets the HTTP response header of this response.
 //
 // If StatusCode is not called, the default status code will be 200 OK.
@@ -23,6 +67,57 @@ func (r *Response) setStatusCode(statusCode int) {
 	r.statusCode = statusCode
 }
 
+// StopPolling sets the status code to 286 Stop Polling, which tells HTMX
+// to stop polling. This is the same as StatusCode([htmx.StatusStopPolling]).
+//
+// For more info, see https://htmx.org/docs/#load_polling
+func (r Response) StopPolling() Response {
+	r.setStatusCode(StatusStopPolling)
+	return r
+}
+
+// CancelPolling sets the status code to 286 Stop Polling, which tells HTMX
+// to stop polling, leaving the other headers of the response intact.
+//
+// This is the same as [htmx.Response.StopPolling]. The 286 status code is what HTMX
+// honors to stop 'every' polling; triggering an event alone does not stop it.
+//
+// For more info, see https://htmx.org/docs/#load_polling
+func (r Response) CancelPolling() Response {
+	return r.StopPolling()
+}
+
+// StopPollingTrigger sets the status code to 286 Stop Polling and triggers the given event,
+// so client-side code can observe that polling stopped, e.g. to update a progress indicator.
+//
+// HTMX stops polling because of the status code. The event is only for your own listeners.
+//
+// Sets the 'HX-Trigger' header.
+//
+// For more info, see https://htmx.org/docs/#load_polling
+func (r Response) StopPollingTrigger(eventName string) Response {
+	return r.
+		CancelPolling().
+		AddTrigger(Trigger(eventName))
+}
+
+// NotModified sets the status code to 304 Not Modified.
+func (r Response) NotModified() Response {
+	r.setStatusCode(http.StatusNotModified)
+	return r
+}
+
+// StopPollingIf sets the status code to 286 Stop Polling if done is true,
+// which tells HTMX to stop polling. Otherwise, the response is unchanged.
+//
+// For more info, see https://htmx.org/docs/#load_polling
+func (r Response) StopPollingIf(done bool) Response {
+	if done {
+		r.setStatusCode(StatusStopPolling)
+	}
+	return r
+}
+
 // Location allows you to do a client-side redirect that does not do a full page reload.
 //
 // If you want to redirect to a specific target on the page rather than the default of document.body,
@@ -83,11 +178,19 @@ type locationContext struct {
 //
 // Sets the 'HX-Location' header.
 //
+// The path must not be empty; if it is, the error is returned by [htmx.Response.Write].
+//
 // For more info, see https://htmx.org/headers/hx-location/
 func (r Response) LocationWithContext(path string, ctx LocationContext) Response {
 	// Replace the error at the start because the last errors shouldn't really matter
 	r.locationWithContextErr = make([]error, 0)
 
+	if path == "" {
+		r.locationWithContextErr = append(r.locationWithContextErr,
+			errors.New("'HX-Location' path must not be empty"))
+		return r
+	}
+
 	c := locationContext{
 		Path:    path,
 		Source:  ctx.Source,
@@ -100,7 +203,7 @@ func (r Response) LocationWithContext(path string, ctx LocationContext) Response
 		Select:  ctx.Select,
 	}
 
-	bytes, err := json.Marshal(c)
+	bytes, err := encodeJSON(c)
 	if err != nil {
 		r.locationWithContextErr = append(r.locationWithContextErr, err)
 		return r
@@ -111,6 +214,20 @@ func (r Response) LocationWithContext(path string, ctx LocationContext) Response
 	return r
 }
 
+// LocationTarget does a client-side redirect that does not do a full page reload,
+// swapping the response into the element found by the given CSS selector.
+//
+// This is the same as [htmx.Response.LocationWithContext] with only the target set.
+//
+// Sets the 'HX-Location' header.
+//
+// For more info, see https://htmx.org/headers/hx-location/
+func (r Response) LocationTarget(path string, target string) Response {
+	return r.LocationWithContext(path, LocationContext{
+		Target: target,
+	})
+}
+
 // PushURL pushes a new URL into the browser location history.
 //
 // Sets the same header as [htmx.Response.PreventPushURL], overwriting previous set headers.
@@ -123,6 +240,84 @@ func (r Response) PushURL(url string) Response {
 	return r
 }
 
+// PushURLParsed pushes a parsed URL into the browser location history.
+//
+// If u is nil, the response is unchanged.
+//
+// Sets the 'HX-Push-Url' header.
+//
+// For more info, see https://htmx.org/headers/hx-push-url/
+func (r Response) PushURLParsed(u *url.URL) Response {
+	if u == nil {
+		return r
+	}
+	return r.PushURL(u.String())
+}
+
+// PushURLWithHash pushes a new URL with the given hash fragment into the browser location history.
+//
+// Any fragment already in the path is replaced, and a leading '#' in hash is optional,
+// so the URL never ends up with a double '#'. If hash is empty, the path is pushed without a fragment.
+//
+// Sets the 'HX-Push-Url' header.
+//
+// For more info, see https://htmx.org/headers/hx-push-url/
+func (r Response) PushURLWithHash(path string, hash string) Response {
+	path, _, _ = strings.Cut(path, "#")
+	hash = strings.TrimPrefix(hash, "#")
+
+	if hash == "" {
+		return r.PushURL(path)
+	}
+	return r.PushURL(path + "#" + hash)
+}
+
+// BoostAwarePushURL pushes a new URL into the browser location history only if the
+// request was made via an element using 'hx-boost'.
+//
+// For other requests, the response is unchanged and history is left to the
+// normal HTMX handling (e.g. 'hx-push-url' on the triggering element).
+//
+// Sets the 'HX-Push-Url' header for boosted requests.
+//
+// For more info, see https://htmx.org/headers/hx-push-url/
+func (r Response) BoostAwarePushURL(req *http.Request, url string) Response {
+	if !IsBoosted(req) {
+		return r
+	}
+	return r.PushURL(url)
+}
+
+// PushURLRelative pushes a URL resolved against the current URL of the request
+// into the browser location history, e.g. "../edit" or "?page=2".
+//
+// The current URL is read from the 'HX-Current-URL' header. A resolved URL on the same
+// origin is pushed as an absolute path, keeping its query and fragment.
+// If the header does not exist, or either URL can't be parsed, ref is pushed as is.
+//
+// Sets the 'HX-Push-Url' header.
+//
+// For more info, see https://htmx.org/headers/hx-push-url/
+func (r Response) PushURLRelative(req *http.Request, ref string) Response {
+	current, ok := GetCurrentURLParsed(req)
+	if !ok {
+		return r.PushURL(ref)
+	}
+
+	refURL, err := url.Parse(ref)
+	if err != nil {
+		return r.PushURL(ref)
+	}
+
+	resolved := current.ResolveReference(refURL)
+	if resolved.Scheme == current.Scheme && resolved.Host == current.Host {
+		resolved.Scheme = ""
+		resolved.User = nil
+		resolved.Host = ""
+	}
+	return r.PushURLParsed(resolved)
+}
+
 // PreventPushURL prevents the browser’s history from being updated.
 //
 // Sets the same header as [htmx.Response.PushURL], overwriting previous set headers.
@@ -155,6 +350,16 @@ func (r Response) Refresh(shouldRefresh bool) Response {
 	return r
 }
 
+// RefreshPage makes the client-side do a full refresh of the page.
+//
+// This is the same as Refresh(true). Don't combine it with [htmx.Response.Redirect],
+// since the client refreshes the page instead of following the redirect.
+//
+// Sets the 'HX-Refresh' header to 'true'.
+func (r Response) RefreshPage() Response {
+	return r.Refresh(true)
+}
+
 // ReplaceURL replaces the current URL in the browser location history.
 //
 // Sets the same header as [htmx.Response.PreventReplaceURL], overwriting previous set headers.
@@ -167,6 +372,20 @@ func (r Response) ReplaceURL(url string) Response {
 	return r
 }
 
+// ReplaceURLParsed replaces the current URL in the browser location history with a parsed URL.
+//
+// If u is nil, the response is unchanged.
+//
+// Sets the 'HX-Replace-Url' header.
+//
+// For more info, see https://htmx.org/headers/hx-replace-url/
+func (r Response) ReplaceURLParsed(u *url.URL) Response {
+	if u == nil {
+		return r
+	}
+	return r.ReplaceURL(u.String())
+}
+
 // PreventReplaceURL prevents the browser’s current URL from being updated.
 //
 // Sets the same header as [htmx.Response.ReplaceURL], overwriting previous set headers.
@@ -183,12 +402,134 @@ func (r Response) PreventReplaceURL() Response {
 //
 // Sets the 'HX-Reswap' header.
 //
+// If enabled with [htmx.SetDefaultIgnoreTitle], the 'ignoreTitle:true' modifier is added
+// unless the swap strategy sets 'ignoreTitle' itself.
+//
 // For more info, see https://htmx.org/attributes/hx-swap/
 func (r Response) Reswap(s SwapStrategy) Response {
+	if defaultIgnoreTitle.Load() && !s.hasModifier("ignoreTitle") {
+		s = s.IgnoreTitle(true)
+	}
 	r.headers[HeaderReswap] = s.swapString()
 	return r
 }
 
+// ReswapInnerHTML makes the response replace the inner html of the target element.
+//
+// This is the same as Reswap([htmx.SwapInnerHTML]).
+//
+// Sets the 'HX-Reswap' header.
+func (r Response) ReswapInnerHTML() Response {
+	return r.Reswap(SwapInnerHTML)
+}
+
+// ReswapOuterHTML makes the response replace the entire target element.
+//
+// This is the same as Reswap([htmx.SwapOuterHTML]).
+//
+// Sets the 'HX-Reswap' header.
+func (r Response) ReswapOuterHTML() Response {
+	return r.Reswap(SwapOuterHTML)
+}
+
+// ReswapBeforeBegin makes the response insert the response before the target element.
+//
+// This is the same as Reswap([htmx.SwapBeforeBegin]).
+//
+// Sets the 'HX-Reswap' header.
+func (r Response) ReswapBeforeBegin() Response {
+	return r.Reswap(SwapBeforeBegin)
+}
+
+// ReswapAfterBegin makes the response insert the response before the first child of the target element.
+//
+// This is the same as Reswap([htmx.SwapAfterBegin]).
+//
+// Sets the 'HX-Reswap' header.
+func (r Response) ReswapAfterBegin() Response {
+	return r.Reswap(SwapAfterBegin)
+}
+
+// ReswapBeforeEnd makes the response insert the response after the last child of the target element.
+//
+// This is the same as Reswap([htmx.SwapBeforeEnd]).
+//
+// Sets the 'HX-Reswap' header.
+func (r Response) ReswapBeforeEnd() Response {
+	return r.Reswap(SwapBeforeEnd)
+}
+
+// ReswapAfterEnd makes the response insert the response after the target element.
+//
+// This is the same as Reswap([htmx.SwapAfterEnd]).
+//
+// Sets the 'HX-Reswap' header.
+func (r Response) ReswapAfterEnd() Response {
+	return r.Reswap(SwapAfterEnd)
+}
+
+// ReswapDelete makes the response delete the target element regardless of the response.
+//
+// This is the same as Reswap([htmx.SwapDelete]).
+//
+// Sets the 'HX-Reswap' header.
+func (r Response) ReswapDelete() Response {
+	return r.Reswap(SwapDelete)
+}
+
+// ReswapNone makes the response not swap in content from the response.
+//
+// This is the same as Reswap([htmx.SwapNone]).
+//
+// Sets the 'HX-Reswap' header.
+func (r Response) ReswapNone() Response {
+	return r.Reswap(SwapNone)
+}
+
+// ReswapRaw sets the 'HX-Reswap' header to the given value verbatim, for swap
+// modifiers that [SwapStrategy] does not support.
+//
+// No validation is performed, and the [htmx.SetDefaultIgnoreTitle] policy is not applied.
+// Overwrites any value set by a previous [htmx.Response.Reswap].
+//
+// Sets the 'HX-Reswap' header.
+//
+// For more info, see https://htmx.org/attributes/hx-swap/
+func (r Response) ReswapRaw(value string) Response {
+	r.headers[HeaderReswap] = value
+	return r
+}
+
+// GetReswap returns the swap strategy set by [htmx.Response.Reswap].
+//
+// Returns false if the 'HX-Reswap' header is not set.
+func (r Response) GetReswap() (SwapStrategy, bool) {
+	v, ok := r.headers[HeaderReswap]
+	return SwapStrategy(v), ok
+}
+
+// ReswapModify applies fn to the swap strategy currently set by [htmx.Response.Reswap]
+// and sets the result, e.g. to add a modifier to the swap of a base response.
+//
+// If the 'HX-Reswap' header is not set, fn is called with [htmx.SwapDefault].
+//
+// Example:
+//
+//	res.ReswapModify(func(s htmx.SwapStrategy) htmx.SwapStrategy {
+//		return s.Transition(true)
+//	})
+//
+// Sets the 'HX-Reswap' header.
+//
+// For more info, see https://htmx.org/attributes/hx-swap/
+func (r Response) ReswapModify(fn func(SwapStrategy) SwapStrategy) Response {
+	current, ok := r.GetReswap()
+	if !ok {
+		current = SwapDefault
+	}
+	return r.Reswap(fn(current))
+}
+
 // Retarget accepts a CSS selector that updates the target of the content update to a different element on the page. Overrides an existing 'hx-select' on the triggering element.
 //
 // Sets the 'HX-Retarget' header.
@@ -202,6 +543,10 @@ func (r Response) Retarget(cssSelector string) Response {
 // Reselect accepts a CSS selector that allows you to choose which part of the response is used to be swapped in.
 // Overrides an existing hx-select on the triggering element.
 //
+// The selected content is swapped in with the swap strategy of the request, so combining
+// Reselect with a 'none' or 'delete' [htmx.Response.Reswap] has no effect.
+// [htmx.Response.Validate] reports this combination.
+//
 // Sets the 'HX-Reselect' header.
 //
 // For more info, see https://htmx.org/attributes/hx-select/
@@ -297,37 +642,296 @@ func TriggerObject(eventName string, detailObject any) triggerObject {
 	}
 }
 
+// TriggerNamed returns an event trigger with no additional details, for event names
+// of your own string type.
+//
+// This is the same as [htmx.Trigger], without converting the event name to a string.
+//
+// Example:
+//
+//	type AppEvent string
+//
+//	const ContactAdded AppEvent = "contactAdded"
+//
+//	htmx.TriggerNamed(ContactAdded)
+//
+// For more info, see https://htmx.org/headers/hx-trigger/
+func TriggerNamed[E ~string](event E) triggerPlain {
+	return Trigger(string(event))
+}
+
+// TriggerDetailNamed returns an event trigger with one detail string, for event names
+// of your own string type.
+//
+// This is the same as [htmx.TriggerDetail], without converting the event name to a string.
+//
+// For more info, see https://htmx.org/headers/hx-trigger/
+func TriggerDetailNamed[E ~string](event E, detailValue string) triggerDetail {
+	return TriggerDetail(string(event), detailValue)
+}
+
+// TriggerObjectNamed returns an event trigger with a given detail object, for event names
+// of your own string type.
+//
+// This is the same as [htmx.TriggerObject], without converting the event name to a string.
+//
+// For more info, see https://htmx.org/headers/hx-trigger/
+func TriggerObjectNamed[E ~string](event E, detailObject any) triggerObject {
+	return TriggerObject(string(event), detailObject)
+}
+
+// TriggerValue returns an event trigger whose detail is a scalar value, such as a number
+// or boolean, without converting it to a string like [htmx.TriggerDetail] does.
+//
+// The value is used as the detail itself, which is the same as passing it to [htmx.TriggerObject].
+//
+// Example:
+//
+//	htmx.TriggerValue("count", 5)
+//
+// Output header:
+//
+//	HX-Trigger: {"count":5}
+//
+// For more info, see https://htmx.org/headers/hx-trigger/
+func TriggerValue(eventName string, value any) triggerObject {
+	return TriggerObject(eventName, value)
+}
+
+// TriggerTyped returns an event trigger with a given detail object of a type fixed at the call site.
+//
+// This behaves like [htmx.TriggerObject], but lets you write wrappers that only accept
+// detail types you know serialize to JSON.
+//
+// Example:
+//
+//	type Message struct {
+//		Level   string `json:"level"`
+//		Message string `json:"message"`
+//	}
+//
+//	func ShowMessage(m Message) htmx.EventTrigger {
+//		return htmx.TriggerTyped("showMessage", m)
+//	}
+//
+// For more info, see https://htmx.org/headers/hx-trigger/
+func TriggerTyped[T any](eventName string, detail T) triggerObject {
+	return TriggerObject(eventName, detail)
+}
+
+// ToastEvent is the name of the event triggered by [htmx.ToastTrigger].
+var ToastEvent = "showMessage"
+
+// Detail object of [htmx.ToastTrigger].
+type toastDetail struct {
+	Level   string `json:"level"`
+	Message string `json:"message"`
+}
+
+// ToastTrigger returns an event trigger for a toast notification with a severity level
+// and a message.
+//
+// The event is named after [htmx.ToastEvent].
+//
+// Example:
+//
+//	htmx.ToastTrigger("info", "Here Is A Message")
+//
+// Output header:
+//
+//	HX-Trigger: {"showMessage":{"level":"info","message":"Here Is A Message"}}
+//
+// For more info, see https://htmx.org/headers/hx-trigger/
+func ToastTrigger(level string, message string) triggerObject {
+	return TriggerObject(ToastEvent, toastDetail{
+		Level:   level,
+		Message: message,
+	})
+}
+
+// ConfigEvent is the name of the event triggered by [htmx.ConfigTrigger].
+var ConfigEvent = "htmx:config"
+
+// ConfigTrigger returns an event trigger carrying changes to the client-side HTMX config,
+// such as 'timeout' or 'withCredentials'.
+//
+// The event is named after [htmx.ConfigEvent]. HTMX does not handle this event itself,
+// so the client needs a listener that applies the detail object to 'htmx.config':
+//
+//	document.body.addEventListener("htmx:config", (e) => Object.assign(htmx.config, e.detail))
+//
+// Example:
+//
+//	htmx.ConfigTrigger(map[string]any{"timeout": 5000})
+//
+// Output header:
+//
+//	HX-Trigger: {"htmx:config":{"timeout":5000}}
+//
+// For more info, see https://htmx.org/docs/#config
+func ConfigTrigger(config map[string]any) triggerObject {
+	return TriggerObject(ConfigEvent, config)
+}
+
+// Detail object of [htmx.TriggerScoped].
+type scopedDetail struct {
+	Scope  string `json:"scope"`
+	Detail any    `json:"detail"`
+}
+
+// TriggerScoped returns an event trigger whose detail carries a CSS selector hinting
+// which part of the page the event is meant for.
+// The detail object **must** be serializable to JSON.
+//
+// HTMX has no header for scoping events, so the scope is part of the event detail
+// and the client-side listener is responsible for honoring it:
+//
+//	document.body.addEventListener("itemUpdated", (evt) => {
+//	  const scope = document.querySelector(evt.detail.scope);
+//	  // handle evt.detail.detail within scope
+//	});
+//
+// Example:
+//
+//	htmx.TriggerScoped("itemUpdated", "#cart", map[string]string{"id": "42"})
+//
+// Output header:
+//
+//	HX-Trigger: {"itemUpdated":{"scope":"#cart","detail":{"id":"42"}}}
+//
+// For more info, see https://htmx.org/headers/hx-trigger/
+func TriggerScoped(eventName string, scopeSelector string, detail any) triggerObject {
+	return TriggerObject(eventName, scopedDetail{
+		Scope:  scopeSelector,
+		Detail: detail,
+	})
+}
+
+// Detail object of [htmx.TriggerAfterDelay].
+type delayDetail struct {
+	DelayMs int64 `json:"delayMs"`
+}
+
+// TriggerAfterDelay returns an event trigger whose detail carries a delay in milliseconds,
+// for client-side actions that should run after some time (e.g. refreshing a region)
+// without polling the server.
+//
+// HTMX fires the event immediately, so the client-side listener is responsible for
+// waiting the delay before acting:
+//
+//	document.body.addEventListener("refreshStats", (evt) => {
+//	  setTimeout(() => htmx.trigger("#stats", "refresh"), evt.detail.delayMs);
+//	});
+//
+// Example:
+//
+//	htmx.TriggerAfterDelay("refreshStats", 5*time.Second)
+//
+// Output header:
+//
+//	HX-Trigger: {"refreshStats":{"delayMs":5000}}
+//
+// For more info, see https://htmx.org/headers/hx-trigger/
+func TriggerAfterDelay(eventName string, delay time.Duration) triggerObject {
+	return TriggerObject(eventName, delayDetail{
+		DelayMs: delay.Milliseconds(),
+	})
+}
+
+// triggerParts returns the event name and detail of a trigger.
+// Plain triggers have an empty string as their detail.
+func triggerParts(t EventTrigger) (string, any) {
+	switch v := t.(type) {
+	case triggerPlain:
+		return string(v), ""
+	case triggerObject:
+		return v.eventName, v.object
+	case triggerDetail:
+		return v.eventName, v.value
+	}
+	return "", nil
+}
+
+// TriggerEncoder is the function used to encode the JSON in the 'HX-Trigger',
+// 'HX-Trigger-After-Settle', 'HX-Trigger-After-Swap' and 'HX-Location' headers.
+// Defaults to [json.Marshal].
+//
+// Replace it to customize the encoding, e.g. with a [json.Encoder] that has
+// SetEscapeHTML(false), or with a faster JSON library. Leading and trailing
+// whitespace in the output, such as the newline added by [json.Encoder.Encode], is trimmed.
+//
+// TriggerEncoder is not synchronized, so set it once before writing any responses.
+// It may be called concurrently by different responses.
+var TriggerEncoder func(v any) ([]byte, error) = json.Marshal
+
+// encodeJSON encodes a value with [htmx.TriggerEncoder].
+func encodeJSON(v any) ([]byte, error) {
+	b, err := TriggerEncoder(v)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.TrimSpace(b), nil
+}
+
 // triggersToString converts a slice of triggers into a header value
 // for headers like 'HX-Trigger'.
+//
+// If all triggers are plain, the event names are joined with ", " in order.
+// Otherwise, the triggers are written as one JSON object with the events in
+// insertion order. Only the plain form can repeat an event: a JSON object can
+// hold each event name once, so a repeated event keeps the position of its first
+// occurrence with the detail of its last, the same result htmx gets from
+// parsing repeated keys with JSON.parse.
 func triggersToString(triggers []EventTrigger) (string, error) {
-	simpleEvents := make([]string, 0)
-	detailEvents := make(map[string]any)
-
+	allPlain := true
 	for _, t := range triggers {
-		switch v := t.(type) {
-		case triggerPlain:
-			simpleEvents = append(simpleEvents, string(v))
-		case triggerObject:
-			detailEvents[v.eventName] = v.object
-		case triggerDetail:
-			detailEvents[v.eventName] = v.value
+		if _, ok := t.(triggerPlain); !ok {
+			allPlain = false
+			break
 		}
 	}
 
-	if len(detailEvents) == 0 {
-		return strings.Join(simpleEvents, ", "), nil
-	} else {
-		for _, evt := range simpleEvents {
-			detailEvents[evt] = ""
+	if allPlain {
+		events := make([]string, 0, len(triggers))
+		for _, t := range triggers {
+			events = append(events, string(t.(triggerPlain)))
 		}
+		return strings.Join(events, ", "), nil
+	}
 
-		bytes, err := json.Marshal(detailEvents)
+	names := make([]string, 0, len(triggers))
+	details := make(map[string]any, len(triggers))
+	for _, t := range triggers {
+		eventName, detail := triggerParts(t)
+		if _, ok := details[eventName]; !ok {
+			names = append(names, eventName)
+		}
+		details[eventName] = detail
+	}
+
+	// Built manually because marshalling a map sorts the keys
+	var b strings.Builder
+	b.WriteByte('{')
+	for i, eventName := range names {
+		key, err := encodeJSON(eventName)
+		if err != nil {
+			return "", err
+		}
+		value, err := encodeJSON(details[eventName])
 		if err != nil {
 			return "", err
 		}
 
-		return string(bytes), nil
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.Write(key)
+		b.WriteByte(':')
+		b.Write(value)
 	}
+	b.WriteByte('}')
+
+	return b.String(), nil
 }
 
 // AddTrigger adds trigger(s) for events that trigger as soon as the response is received.
@@ -339,7 +943,7 @@ func triggersToString(triggers []EventTrigger) (string, error) {
 // For more info, see https://htmx.org/headers/hx-trigger/
 func (r Response) AddTrigger(trigger ...EventTrigger) Response {
 	r.initTriggers()
-	r.triggers = append(r.triggers, trigger...)
+	r.triggers = append(r.triggers[:len(r.triggers):len(r.triggers)], trigger...)
 	return r
 }
 
@@ -352,7 +956,7 @@ func (r Response) AddTrigger(trigger ...EventTrigger) Response {
 // For more info, see https://htmx.org/headers/hx-trigger/
 func (r Response) AddTriggerAfterSettle(trigger ...EventTrigger) Response {
 	r.initTriggersAfterSettle()
-	r.triggersAfterSettle = append(r.triggersAfterSettle, trigger...)
+	r.triggersAfterSettle = append(r.triggersAfterSettle[:len(r.triggersAfterSettle):len(r.triggersAfterSettle)], trigger...)
 	return r
 }
 
@@ -365,10 +969,179 @@ func (r Response) AddTriggerAfterSettle(trigger ...EventTrigger) Response {
 // For more info, see https://htmx.org/headers/hx-trigger/
 func (r Response) AddTriggerAfterSwap(trigger ...EventTrigger) Response {
 	r.initTriggersAfterSwap()
-	r.triggersAfterSwap = append(r.triggersAfterSwap, trigger...)
+	r.triggersAfterSwap = append(r.triggersAfterSwap[:len(r.triggersAfterSwap):len(r.triggersAfterSwap)], trigger...)
 	return r
 }
 
+// DomainEvent is an application event, such as one collected by an in-process event bus,
+// that can be translated into an HTMX trigger with [htmx.Response.AddTriggersFromEvents].
+type DomainEvent interface {
+	// The name of the event.
+	EventName() string
+}
+
+// AddTriggersFromEvents adds triggers for the given domain events to 'HX-Trigger'.
+//
+// Each event is passed to mapFn, which returns the trigger for the event,
+// or false to skip it. If mapFn is nil, every event is added as a plain trigger
+// named after its [htmx.DomainEvent.EventName].
+//
+// Sets the 'HX-Trigger' header.
+//
+// For more info, see https://htmx.org/headers/hx-trigger/
+func (r Response) AddTriggersFromEvents(events []DomainEvent, mapFn func(DomainEvent) (EventTrigger, bool)) Response {
+	if mapFn == nil {
+		mapFn = func(e DomainEvent) (EventTrigger, bool) {
+			return Trigger(e.EventName()), true
+		}
+	}
+
+	triggers := make([]EventTrigger, 0, len(events))
+	for _, e := range events {
+		if t, ok := mapFn(e); ok {
+			triggers = append(triggers, t)
+		}
+	}
+
+	if len(triggers) == 0 {
+		return r
+	}
+	return r.AddTrigger(triggers...)
+}
+
+// ValidationErrorsEvent is the name of the event triggered by [htmx.Response.TriggerValidationErrors].
+var ValidationErrorsEvent = "validationErrors"
+
+// TriggerValidationErrors adds a trigger carrying form field errors, keyed by field name,
+// so a client-side listener can highlight the invalid fields.
+//
+// The event is named after [htmx.ValidationErrorsEvent].
+//
+// Example:
+//
+//	htmx.NewResponse().TriggerValidationErrors(map[string]string{
+//	  "email": "Email is required",
+//	})
+//
+// Output header:
+//
+//	HX-Trigger: {"validationErrors":{"email":"Email is required"}}
+//
+// Sets the 'HX-Trigger' header.
+//
+// For more info, see https://htmx.org/headers/hx-trigger/
+func (r Response) TriggerValidationErrors(fieldErrors map[string]string) Response {
+	return r.AddTrigger(TriggerObject(ValidationErrorsEvent, fieldErrors))
+}
+
+// EchoTrigger adds a trigger for an 'echo' event with the ID of the element that
+// triggered the request as its detail, to trace which element caused which update
+// while debugging.
+//
+// If the request has no 'HX-Trigger' header, the response is unchanged.
+//
+// Output header:
+//
+//	HX-Trigger: {"echo":"<triggering element ID>"}
+func (r Response) EchoTrigger(req *http.Request) Response {
+	id, ok := GetTrigger(req)
+	if !ok {
+		return r
+	}
+	return r.AddTrigger(TriggerDetail("echo", id))
+}
+
+// CloseUI adds plain triggers for events that close or reset parts of the UI,
+// such as 'closeModal' or 'clearForm', typically after a successful save.
+//
+// This is the same as calling [htmx.Response.AddTrigger] with [htmx.Trigger] for each event.
+//
+// Sets the 'HX-Trigger' header.
+//
+// For more info, see https://htmx.org/headers/hx-trigger/
+func (r Response) CloseUI(events ...string) Response {
+	triggers := make([]EventTrigger, 0, len(events))
+	for _, evt := range events {
+		triggers = append(triggers, Trigger(evt))
+	}
+	return r.AddTrigger(triggers...)
+}
+
+// RemoveTrigger removes all triggers for the given event name that were added
+// with [htmx.Response.AddTrigger].
+//
+// If no trigger matches, the response is unchanged.
+func (r Response) RemoveTrigger(eventName string) Response {
+	r.triggers = removeTriggers(r.triggers, eventName)
+	return r
+}
+
+// RemoveTriggerAfterSettle removes all triggers for the given event name that were added
+// with [htmx.Response.AddTriggerAfterSettle].
+//
+// If no trigger matches, the response is unchanged.
+func (r Response) RemoveTriggerAfterSettle(eventName string) Response {
+	r.triggersAfterSettle = removeTriggers(r.triggersAfterSettle, eventName)
+	return r
+}
+
+// RemoveTriggerAfterSwap removes all triggers for the given event name that were added
+// with [htmx.Response.AddTriggerAfterSwap].
+//
+// If no trigger matches, the response is unchanged.
+func (r Response) RemoveTriggerAfterSwap(eventName string) Response {
+	r.triggersAfterSwap = removeTriggers(r.triggersAfterSwap, eventName)
+	return r
+}
+
+// ClearTriggers removes all triggers added with [htmx.Response.AddTrigger],
+// [htmx.Response.AddTriggerAfterSettle] and [htmx.Response.AddTriggerAfterSwap].
+//
+// Responses sharing triggers with this response are not affected.
+func (r Response) ClearTriggers() Response {
+	r.triggers = nil
+	r.triggersAfterSettle = nil
+	r.triggersAfterSwap = nil
+	return r
+}
+
+// HasTriggers returns true if the response has any triggers for
+// 'HX-Trigger', 'HX-Trigger-After-Settle' or 'HX-Trigger-After-Swap'.
+func (r Response) HasTriggers() bool {
+	return len(r.triggers) > 0 || len(r.triggersAfterSettle) > 0 || len(r.triggersAfterSwap) > 0
+}
+
+// removeTriggers returns a new slice of the triggers without the ones for the given event name,
+// so the original slice shared with other responses is not modified.
+//
+// Returns nil if no triggers are left, so the header is not written.
+func removeTriggers(triggers []EventTrigger, eventName string) []EventTrigger {
+	var filtered []EventTrigger
+	for _, t := range triggers {
+		if name, _ := triggerParts(t); name == eventName {
+			continue
+		}
+		filtered = append(filtered, t)
+	}
+	return filtered
+}
+
+// Triggers returns copies of the triggers added to the response for 'HX-Trigger',
+// 'HX-Trigger-After-Settle' and 'HX-Trigger-After-Swap', in that order.
+//
+// Modifying the returned slices does not affect the response.
+func (r Response) Triggers() (triggers, afterSettle, afterSwap []EventTrigger) {
+	return copyTriggers(r.triggers), copyTriggers(r.triggersAfterSettle), copyTriggers(r.triggersAfterSwap)
+}
+
+// copyTriggers returns a copy of a trigger slice, keeping nil slices nil.
+func copyTriggers(triggers []EventTrigger) []EventTrigger {
+	if triggers == nil {
+		return nil
+	}
+	return append(make([]EventTrigger, 0, len(triggers)), triggers...)
+}
+
 // Lazily init the triggers slice because not all responses
 // use triggers
 func (r *Response) initTriggers() {
@@ -392,3 +1165,185 @@ func (r *Response) initTriggersAfterSwap() {
 		r.triggersAfterSwap = make([]EventTrigger, 0)
 	}
 }
+
+// Download marks the response as a file download with the given filename and content type.
+//
+// HTMX swaps response bodies into the page instead of saving them, so the body of
+// a download is not handled by the browser when requested through HTMX. The usual
+// approach is to [htmx.Response.Redirect] to a URL that serves the file using this
+// method, or to handle the body with client-side JavaScript.
+//
+// Sets the 'Content-Disposition' header to 'attachment; filename=<filename>'
+// and the 'Content-Type' header.
+func (r Response) Download(filename string, contentType string) Response {
+	disposition := mime.FormatMediaType("attachment", map[string]string{
+		"filename": filename,
+	})
+	return r.
+		Header("Content-Disposition", disposition).
+		ContentType(contentType)
+}
+
+// ScrollToNewEvent is the name of the event triggered by [htmx.Response.AppendAndScroll].
+var ScrollToNewEvent = "scrollToNew"
+
+// AppendAndScroll appends the response to the end of the element found by the given
+// CSS selector, then triggers an event so the client can scroll the new element into view.
+//
+// The event is named after [htmx.ScrollToNewEvent] and fires after the settle step
+// with the list selector as its detail, for a listener like:
+//
+//	document.body.addEventListener("scrollToNew", (evt) => {
+//	  document.querySelector(evt.detail.value).lastElementChild.scrollIntoView();
+//	});
+//
+// Sets the 'HX-Retarget', 'HX-Reswap' and 'HX-Trigger-After-Settle' headers.
+func (r Response) AppendAndScroll(listSelector string) Response {
+	return r.
+		Retarget(listSelector).
+		Reswap(SwapBeforeEnd).
+		AddTriggerAfterSettle(TriggerDetail(ScrollToNewEvent, listSelector))
+}
+
+// TraceIDHeader is the name of the header set by [htmx.Response.TraceID].
+var TraceIDHeader = "X-Trace-Id"
+
+// TraceIDEvent is the name of the event that [htmx.Response.TraceID] mirrors the trace ID into,
+// so client-side logs can correlate requests.
+//
+// If empty, the trace ID is not mirrored into an event.
+var TraceIDEvent = ""
+
+// TraceID sets a correlation/trace ID on the response for tracing requests across HTMX round-trips.
+//
+// Sets the header named by [htmx.TraceIDHeader], which is 'X-Trace-Id' by default.
+// If [htmx.TraceIDEvent] is set, also adds a trigger for that event with the ID as its detail.
+func (r Response) TraceID(id string) Response {
+	r = r.Header(TraceIDHeader, id)
+	if TraceIDEvent != "" {
+		r = r.AddTrigger(TriggerDetail(TraceIDEvent, id))
+	}
+	return r
+}
+
+// TakeoverPage retargets the response to the '<body>' element so a full page layout
+// replaces the content of the page, e.g. after logging in from a fragment.
+//
+// The swap defaults to 'innerHTML'. To replace the '<body>' element itself,
+// override it with [htmx.Response.Reswap] using [htmx.SwapOuterHTML].
+//
+// Unlike [htmx.Response.Redirect] and [htmx.Response.Refresh], this does not
+// make the browser load a new page, so the response itself must render the new page
+// and the URL stays the same unless combined with [htmx.Response.PushURL].
+//
+// Sets the 'HX-Retarget' and 'HX-Reswap' headers.
+func (r Response) TakeoverPage() Response {
+	return r.
+		Retarget("body").
+		Reswap(SwapInnerHTML)
+}
+
+// ErrorFragment sets the status code, target and swap of a response that renders an
+// error fragment, e.g. validation errors with a 422 Unprocessable Entity status
+// swapped into an error container.
+//
+// By default, HTMX does not swap the content of responses with 4xx and 5xx status codes.
+// For the fragment to be swapped in, the client must allow it, e.g. with an
+// 'htmx:beforeSwap' event handler that sets 'shouldSwap' to true or with the
+// 'response-targets' extension.
+//
+// Sets the 'HX-Retarget' and 'HX-Reswap' headers.
+//
+// For more info, see https://htmx.org/docs/#modifying_swapping_behavior_with_events
+func (r Response) ErrorFragment(statusCode int, cssSelector string, swap SwapStrategy) Response {
+	return r.
+		StatusCode(statusCode).
+		Retarget(cssSelector).
+		Reswap(swap)
+}
+
+// Navigate pushes a new URL into the browser location history and swaps the response
+// into the main content element found by the given CSS selector, for in-app navigation
+// without a full page load.
+//
+// The swap defaults to 'innerHTML'. Override it with [htmx.Response.Reswap] if needed.
+//
+// Sets the 'HX-Push-Url', 'HX-Retarget' and 'HX-Reswap' headers.
+func (r Response) Navigate(url string, mainSelector string) Response {
+	return r.
+		PushURL(url).
+		Retarget(mainSelector).
+		Reswap(SwapInnerHTML)
+}
+
+// NoCache prevents browsers from caching the response, so fragments aren't shown
+// in place of full pages (or stale content) when navigating through history.
+//
+// Sets the 'Cache-Control' header to 'no-store, max-age=0' and adds 'HX-Request'
+// to the 'Vary' header with [htmx.Response.VaryOnHTMX].
+func (r Response) NoCache() Response {
+	return r.
+		Header("Cache-Control", "no-store, max-age=0").
+		VaryOnHTMX()
+}
+
+// VaryOnHTMX adds 'HX-Request' and the given request headers, such as 'HX-Boosted'
+// or 'HX-Target', to the 'Vary' header, so caches don't serve a fragment for a
+// full page request or the other way around.
+//
+// Existing 'Vary' values, whether set on the response or already on the response writer,
+// are kept, and headers already in the list are not added again.
+//
+// Example:
+//
+//	htmx.NewResponse().VaryOnHTMX(htmx.HeaderBoosted)
+//
+// Output header:
+//
+//	Vary: HX-Request, HX-Boosted
+func (r Response) VaryOnHTMX(headers ...string) Response {
+	names := append([]string{HeaderRequest}, headers...)
+	r.headers[headerVary] = appendVary(r.headers[headerVary], names...)
+	return r
+}
+
+const headerVary = "Vary"
+
+// appendVary adds header names, or comma-separated lists of them, to a comma-separated
+// 'Vary' value, skipping names that are already in it.
+// A 'Vary' value of '*' already covers every header.
+func appendVary(vary string, names ...string) string {
+	values := splitVary(vary)
+
+	for _, name := range names {
+		for _, n := range splitVary(name) {
+			if containsFold(values, n) || containsFold(values, "*") {
+				continue
+			}
+			values = append(values, n)
+		}
+	}
+
+	return strings.Join(values, ", ")
+}
+
+// splitVary splits a comma-separated 'Vary' value into header names.
+func splitVary(vary string) []string {
+	var values []string
+	for _, v := range strings.Split(vary, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// containsFold returns true if values contains s, ignoring case.
+func containsFold(values []string, s string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,1279 @@
+package htmx
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestDownload(t *testing.T) {
+	w := newMockResponseWriter()
+
+	err := NewResponse().
+		Download("report 2024.csv", "text/csv").
+		Write(w)
+	if err != nil {
+		t.Errorf("an error occurred writing a response: %v", err)
+	}
+
+	expectedHeaders := map[string]string{
+		"Content-Disposition": `attachment; filename="report 2024.csv"`,
+		"Content-Type":        "text/csv",
+	}
+
+	for k, v := range expectedHeaders {
+		got := w.header.Get(k)
+		if got != v {
+			t.Errorf("wrong value for header %q. got=%q, want=%q", k, got, v)
+		}
+	}
+}
+
+func TestAppendAndScroll(t *testing.T) {
+	headers, err := NewResponse().
+		AppendAndScroll("#messages").
+		Headers()
+	if err != nil {
+		t.Errorf("an error occurred getting headers: %v", err)
+	}
+
+	expectedHeaders := map[string]string{
+		HeaderRetarget:           "#messages",
+		HeaderReswap:             "beforeend",
+		HeaderTriggerAfterSettle: `{"scrollToNew":"#messages"}`,
+	}
+
+	for k, v := range expectedHeaders {
+		if got := headers[k]; got != v {
+			t.Errorf("wrong value for header %q. got=%q, want=%q", k, got, v)
+		}
+	}
+}
+
+func TestTraceID(t *testing.T) {
+	headers, err := NewResponse().TraceID("abc123").Headers()
+	if err != nil {
+		t.Errorf("an error occurred getting headers: %v", err)
+	}
+
+	if got, want := headers["X-Trace-Id"], "abc123"; got != want {
+		t.Errorf("wrong value for header %q. got=%q, want=%q", "X-Trace-Id", got, want)
+	}
+	if _, ok := headers[HeaderTrigger]; ok {
+		t.Errorf("header %q should not be set", HeaderTrigger)
+	}
+
+	defer func(header, event string) {
+		TraceIDHeader, TraceIDEvent = header, event
+	}(TraceIDHeader, TraceIDEvent)
+	TraceIDHeader, TraceIDEvent = "X-Request-Id", "traceId"
+
+	headers, err = NewResponse().TraceID("abc123").Headers()
+	if err != nil {
+		t.Errorf("an error occurred getting headers: %v", err)
+	}
+
+	expectedHeaders := map[string]string{
+		"X-Request-Id": "abc123",
+		HeaderTrigger:  `{"traceId":"abc123"}`,
+	}
+
+	for k, v := range expectedHeaders {
+		if got := headers[k]; got != v {
+			t.Errorf("wrong value for header %q. got=%q, want=%q", k, got, v)
+		}
+	}
+}
+
+func TestTriggerScoped(t *testing.T) {
+	testCases := []struct {
+		name    string
+		trigger EventTrigger
+		result  string
+	}{
+		{
+			name:    "string detail",
+			trigger: TriggerScoped("itemUpdated", "#cart", "hello"),
+			result:  `{"itemUpdated":{"scope":"#cart","detail":"hello"}}`,
+		},
+		{
+			name:    "object detail",
+			trigger: TriggerScoped("itemUpdated", "#cart", map[string]string{"id": "42"}),
+			result:  `{"itemUpdated":{"scope":"#cart","detail":{"id":"42"}}}`,
+		},
+		{
+			name:    "nil detail",
+			trigger: TriggerScoped("itemUpdated", ".list", nil),
+			result:  `{"itemUpdated":{"scope":".list","detail":null}}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		result, err := triggersToString([]EventTrigger{tc.trigger})
+		if err != nil {
+			t.Errorf("%s: an error occurred marshalling triggers: %v", tc.name, err)
+		}
+		if result != tc.result {
+			t.Errorf(`%s: got: "%v", want: "%v"`, tc.name, result, tc.result)
+		}
+	}
+}
+
+func TestTakeoverPage(t *testing.T) {
+	testCases := []struct {
+		name     string
+		response Response
+		reswap   string
+	}{
+		{
+			name:     "default swap",
+			response: NewResponse().TakeoverPage(),
+			reswap:   "innerHTML",
+		},
+		{
+			name:     "outer html swap",
+			response: NewResponse().TakeoverPage().Reswap(SwapOuterHTML),
+			reswap:   "outerHTML",
+		},
+	}
+
+	for _, tc := range testCases {
+		headers, err := tc.response.Headers()
+		if err != nil {
+			t.Errorf("%s: an error occurred getting headers: %v", tc.name, err)
+		}
+
+		if got := headers[HeaderRetarget]; got != "body" {
+			t.Errorf("%s: wrong value for header %q. got=%q, want=%q", tc.name, HeaderRetarget, got, "body")
+		}
+		if got := headers[HeaderReswap]; got != tc.reswap {
+			t.Errorf("%s: wrong value for header %q. got=%q, want=%q", tc.name, HeaderReswap, got, tc.reswap)
+		}
+	}
+}
+
+func TestGetReswap(t *testing.T) {
+	if _, ok := NewResponse().GetReswap(); ok {
+		t.Errorf("expected reswap to be unset")
+	}
+
+	swap := SwapBeforeEnd.Scroll(Bottom).Transition(true)
+
+	got, ok := NewResponse().Reswap(swap).GetReswap()
+	if !ok {
+		t.Errorf("expected reswap to be set")
+	}
+	if got != swap {
+		t.Errorf(`got: "%v", want: "%v"`, got, swap)
+	}
+}
+
+func TestNavigate(t *testing.T) {
+	headers, err := NewResponse().
+		Navigate("/settings", "#main").
+		Reswap(SwapInnerHTML.Transition(true)).
+		Headers()
+	if err != nil {
+		t.Errorf("an error occurred getting headers: %v", err)
+	}
+
+	expectedHeaders := map[string]string{
+		HeaderPushURL:  "/settings",
+		HeaderRetarget: "#main",
+		HeaderReswap:   "innerHTML transition:true",
+	}
+
+	for k, v := range expectedHeaders {
+		if got := headers[k]; got != v {
+			t.Errorf("wrong value for header %q. got=%q, want=%q", k, got, v)
+		}
+	}
+}
+
+func TestCloseUI(t *testing.T) {
+	headers, err := NewResponse().
+		CloseUI("closeModal", "closeDropdown", "clearForm").
+		Headers()
+	if err != nil {
+		t.Errorf("an error occurred getting headers: %v", err)
+	}
+
+	if got, want := headers[HeaderTrigger], "closeModal, closeDropdown, clearForm"; got != want {
+		t.Errorf("wrong value for header %q. got=%q, want=%q", HeaderTrigger, got, want)
+	}
+}
+
+func TestTriggersToString(t *testing.T) {
+	testCases := []struct {
+		name     string
+		triggers []EventTrigger
+		result   string
+	}{
+		{
+			name:     "plain events",
+			triggers: []EventTrigger{Trigger("b"), Trigger("a"), Trigger("c")},
+			result:   "b, a, c",
+		},
+		{
+			name: "mixed events keep insertion order",
+			triggers: []EventTrigger{
+				TriggerDetail("zebra", "1"),
+				Trigger("apple"),
+				TriggerObject("mango", map[string]string{"level": "info"}),
+			},
+			result: `{"zebra":"1","apple":"","mango":{"level":"info"}}`,
+		},
+		{
+			name:     "repeated plain events",
+			triggers: []EventTrigger{Trigger("a"), Trigger("b"), Trigger("a")},
+			result:   "a, b, a",
+		},
+		{
+			name:     "repeated detail keeps the first position and the last detail",
+			triggers: []EventTrigger{TriggerDetail("a", "1"), Trigger("b"), TriggerDetail("a", "2")},
+			result:   `{"a":"2","b":""}`,
+		},
+		{
+			name:     "plain and detail",
+			triggers: []EventTrigger{Trigger("a"), TriggerObject("a", map[string]string{"level": "info"})},
+			result:   `{"a":{"level":"info"}}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		result, err := triggersToString(tc.triggers)
+		if err != nil {
+			t.Errorf("%s: an error occurred marshalling triggers: %v", tc.name, err)
+		}
+		if result != tc.result {
+			t.Errorf(`%s: got: "%v", want: "%v"`, tc.name, result, tc.result)
+		}
+	}
+}
+
+func TestAddTrigger_Branches(t *testing.T) {
+	base := NewResponse().
+		AddTrigger(Trigger("a"), Trigger("b")).AddTrigger(Trigger("c")).
+		AddTriggerAfterSettle(Trigger("a"), Trigger("b")).AddTriggerAfterSettle(Trigger("c")).
+		AddTriggerAfterSwap(Trigger("a"), Trigger("b")).AddTriggerAfterSwap(Trigger("c"))
+
+	x := base.AddTrigger(Trigger("x")).AddTriggerAfterSettle(Trigger("x")).AddTriggerAfterSwap(Trigger("x"))
+	base.AddTrigger(Trigger("y")).AddTriggerAfterSettle(Trigger("y")).AddTriggerAfterSwap(Trigger("y"))
+
+	headers, err := x.Headers()
+	if err != nil {
+		t.Errorf("an error occurred getting headers: %v", err)
+	}
+
+	for _, k := range []string{HeaderTrigger, HeaderTriggerAfterSettle, HeaderTriggerAfterSwap} {
+		if got, want := headers[k], "a, b, c, x"; got != want {
+			t.Errorf("wrong value for header %q. got=%q, want=%q", k, got, want)
+		}
+	}
+}
+
+func TestRemoveTrigger(t *testing.T) {
+	base := NewResponse().
+		AddTrigger(Trigger("a"), TriggerDetail("b", "1"), TriggerObject("a", map[string]string{})).
+		AddTriggerAfterSettle(Trigger("settled")).
+		AddTriggerAfterSwap(Trigger("swapped"), Trigger("kept"))
+
+	headers, err := base.
+		RemoveTrigger("a").
+		RemoveTriggerAfterSettle("settled").
+		RemoveTriggerAfterSwap("swapped").
+		RemoveTriggerAfterSwap("missing").
+		Headers()
+	if err != nil {
+		t.Errorf("an error occurred getting headers: %v", err)
+	}
+
+	expectedHeaders := map[string]string{
+		HeaderTrigger:          `{"b":"1"}`,
+		HeaderTriggerAfterSwap: "kept",
+	}
+
+	for k, v := range expectedHeaders {
+		if got := headers[k]; got != v {
+			t.Errorf("wrong value for header %q. got=%q, want=%q", k, got, v)
+		}
+	}
+
+	if _, ok := headers[HeaderTriggerAfterSettle]; ok {
+		t.Errorf("header %q should not be set", HeaderTriggerAfterSettle)
+	}
+
+	// Removing does not affect the response it was derived from
+	if got := len(base.triggers); got != 3 {
+		t.Errorf("wrong number of triggers on the original response. got=%v, want=%v", got, 3)
+	}
+
+	// Removing from a response without triggers is a no-op
+	headers, err = NewResponse().RemoveTrigger("a").Headers()
+	if err != nil {
+		t.Errorf("an error occurred getting headers: %v", err)
+	}
+	if _, ok := headers[HeaderTrigger]; ok {
+		t.Errorf("header %q should not be set", HeaderTrigger)
+	}
+}
+
+func TestBoostAwarePushURL(t *testing.T) {
+	boosted := &http.Request{Header: http.Header{}}
+	boosted.Header.Set(HeaderRequest, "true")
+	boosted.Header.Set(HeaderBoosted, "true")
+
+	notBoosted := &http.Request{Header: http.Header{}}
+	notBoosted.Header.Set(HeaderRequest, "true")
+
+	if got, want := NewResponse().BoostAwarePushURL(boosted, "/next").headers[HeaderPushURL], "/next"; got != want {
+		t.Errorf("wrong value for header %q. got=%q, want=%q", HeaderPushURL, got, want)
+	}
+
+	if _, ok := NewResponse().BoostAwarePushURL(notBoosted, "/next").headers[HeaderPushURL]; ok {
+		t.Errorf("header %q should not be set for non-boosted requests", HeaderPushURL)
+	}
+}
+
+func TestTriggerAfterDelay(t *testing.T) {
+	testCases := []struct {
+		name    string
+		trigger EventTrigger
+		result  string
+	}{
+		{
+			name:    "seconds",
+			trigger: TriggerAfterDelay("refreshStats", 5*time.Second),
+			result:  `{"refreshStats":{"delayMs":5000}}`,
+		},
+		{
+			name:    "milliseconds",
+			trigger: TriggerAfterDelay("refreshStats", 250*time.Millisecond),
+			result:  `{"refreshStats":{"delayMs":250}}`,
+		},
+		{
+			name:    "zero",
+			trigger: TriggerAfterDelay("refreshStats", 0),
+			result:  `{"refreshStats":{"delayMs":0}}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		result, err := triggersToString([]EventTrigger{tc.trigger})
+		if err != nil {
+			t.Errorf("%s: an error occurred marshalling triggers: %v", tc.name, err)
+		}
+		if result != tc.result {
+			t.Errorf(`%s: got: "%v", want: "%v"`, tc.name, result, tc.result)
+		}
+	}
+}
+
+func TestPushURLWithHash(t *testing.T) {
+	testCases := []struct {
+		name   string
+		path   string
+		hash   string
+		result string
+	}{
+		{
+			name:   "plain path",
+			path:   "/docs",
+			hash:   "install",
+			result: "/docs#install",
+		},
+		{
+			name:   "hash with leading #",
+			path:   "/docs",
+			hash:   "#install",
+			result: "/docs#install",
+		},
+		{
+			name:   "path with query string",
+			path:   "/search?q=htmx&page=2",
+			hash:   "results",
+			result: "/search?q=htmx&page=2#results",
+		},
+		{
+			name:   "path with existing hash",
+			path:   "/docs?v=2#old",
+			hash:   "#new",
+			result: "/docs?v=2#new",
+		},
+		{
+			name:   "empty hash",
+			path:   "/docs#old",
+			hash:   "",
+			result: "/docs",
+		},
+	}
+
+	for _, tc := range testCases {
+		if got := NewResponse().PushURLWithHash(tc.path, tc.hash).headers[HeaderPushURL]; got != tc.result {
+			t.Errorf(`%s: got: "%v", want: "%v"`, tc.name, got, tc.result)
+		}
+	}
+}
+
+type fakeDomainEvent struct {
+	name     string
+	internal bool
+}
+
+func (e fakeDomainEvent) EventName() string {
+	return e.name
+}
+
+func TestAddTriggersFromEvents(t *testing.T) {
+	events := []DomainEvent{
+		fakeDomainEvent{name: "orderPlaced"},
+		fakeDomainEvent{name: "auditLogged", internal: true},
+		fakeDomainEvent{name: "cartEmptied"},
+	}
+
+	testCases := []struct {
+		name   string
+		mapFn  func(DomainEvent) (EventTrigger, bool)
+		result string
+	}{
+		{
+			name:   "default mapping",
+			mapFn:  nil,
+			result: "orderPlaced, auditLogged, cartEmptied",
+		},
+		{
+			name: "custom mapping skips events",
+			mapFn: func(e DomainEvent) (EventTrigger, bool) {
+				if e.(fakeDomainEvent).internal {
+					return nil, false
+				}
+				return TriggerDetail(e.EventName(), "done"), true
+			},
+			result: `{"orderPlaced":"done","cartEmptied":"done"}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		headers, err := NewResponse().AddTriggersFromEvents(events, tc.mapFn).Headers()
+		if err != nil {
+			t.Errorf("%s: an error occurred getting headers: %v", tc.name, err)
+		}
+		if got := headers[HeaderTrigger]; got != tc.result {
+			t.Errorf(`%s: got: "%v", want: "%v"`, tc.name, got, tc.result)
+		}
+	}
+
+	skipAll := func(DomainEvent) (EventTrigger, bool) { return nil, false }
+	headers, err := NewResponse().AddTriggersFromEvents(events, skipAll).Headers()
+	if err != nil {
+		t.Errorf("an error occurred getting headers: %v", err)
+	}
+	if _, ok := headers[HeaderTrigger]; ok {
+		t.Errorf("header %q should not be set when all events are skipped", HeaderTrigger)
+	}
+}
+
+func TestStopPollingIf(t *testing.T) {
+	if got := NewResponse().StopPollingIf(true).statusCode; got != StatusStopPolling {
+		t.Errorf("wrong status code. want=%v, got=%v", StatusStopPolling, got)
+	}
+
+	if got := NewResponse().StopPollingIf(false).statusCode; got != 0 {
+		t.Errorf("wrong status code. want=%v, got=%v", 0, got)
+	}
+
+	if got := NewResponse().StatusCode(http.StatusAccepted).StopPollingIf(false).statusCode; got != http.StatusAccepted {
+		t.Errorf("wrong status code. want=%v, got=%v", http.StatusAccepted, got)
+	}
+}
+
+func TestTriggerTyped(t *testing.T) {
+	type message struct {
+		Level   string `json:"level"`
+		Message string `json:"message"`
+	}
+
+	typed, err := triggersToString([]EventTrigger{
+		TriggerTyped("showMessage", message{Level: "info", Message: "Saved"}),
+	})
+	if err != nil {
+		t.Errorf("an error occurred marshalling triggers: %v", err)
+	}
+
+	object, err := triggersToString([]EventTrigger{
+		TriggerObject("showMessage", message{Level: "info", Message: "Saved"}),
+	})
+	if err != nil {
+		t.Errorf("an error occurred marshalling triggers: %v", err)
+	}
+
+	if want := `{"showMessage":{"level":"info","message":"Saved"}}`; typed != want || typed != object {
+		t.Errorf(`got: "%v", want: "%v"`, typed, want)
+	}
+}
+
+func TestTriggerValidationErrors(t *testing.T) {
+	headers, err := NewResponse().
+		TriggerValidationErrors(map[string]string{
+			"password": "Password is too short",
+			"email":    "Email is required",
+		}).
+		Headers()
+	if err != nil {
+		t.Errorf("an error occurred getting headers: %v", err)
+	}
+
+	want := `{"validationErrors":{"email":"Email is required","password":"Password is too short"}}`
+	if got := headers[HeaderTrigger]; got != want {
+		t.Errorf("wrong value for header %q. got=%q, want=%q", HeaderTrigger, got, want)
+	}
+
+	defer func(event string) { ValidationErrorsEvent = event }(ValidationErrorsEvent)
+	ValidationErrorsEvent = "formErrors"
+
+	headers, err = NewResponse().
+		TriggerValidationErrors(map[string]string{"email": "Email is required"}).
+		Headers()
+	if err != nil {
+		t.Errorf("an error occurred getting headers: %v", err)
+	}
+
+	want = `{"formErrors":{"email":"Email is required"}}`
+	if got := headers[HeaderTrigger]; got != want {
+		t.Errorf("wrong value for header %q. got=%q, want=%q", HeaderTrigger, got, want)
+	}
+}
+
+func TestEchoTrigger(t *testing.T) {
+	req := &http.Request{Header: http.Header{}}
+	req.Header.Set(HeaderTrigger, "save-button")
+
+	headers, err := NewResponse().EchoTrigger(req).Headers()
+	if err != nil {
+		t.Errorf("an error occurred getting headers: %v", err)
+	}
+	if got, want := headers[HeaderTrigger], `{"echo":"save-button"}`; got != want {
+		t.Errorf("wrong value for header %q. got=%q, want=%q", HeaderTrigger, got, want)
+	}
+
+	headers, err = NewResponse().EchoTrigger(&http.Request{Header: http.Header{}}).Headers()
+	if err != nil {
+		t.Errorf("an error occurred getting headers: %v", err)
+	}
+	if _, ok := headers[HeaderTrigger]; ok {
+		t.Errorf("header %q should not be set", HeaderTrigger)
+	}
+}
+
+func TestNoCache(t *testing.T) {
+	headers, err := NewResponse().NoCache().Headers()
+	if err != nil {
+		t.Errorf("an error occurred getting headers: %v", err)
+	}
+
+	expectedHeaders := map[string]string{
+		"Cache-Control": "no-store, max-age=0",
+		"Vary":          "HX-Request",
+	}
+
+	for k, v := range expectedHeaders {
+		if got := headers[k]; got != v {
+			t.Errorf("wrong value for header %q. got=%q, want=%q", k, got, v)
+		}
+	}
+}
+
+func TestReswapRaw(t *testing.T) {
+	value := "innerHTML  swap:1s customModifier:yes"
+
+	headers, err := NewResponse().
+		Reswap(SwapOuterHTML).
+		ReswapRaw(value).
+		Headers()
+	if err != nil {
+		t.Errorf("an error occurred getting headers: %v", err)
+	}
+
+	if got := headers[HeaderReswap]; got != value {
+		t.Errorf("wrong value for header %q. got=%q, want=%q", HeaderReswap, got, value)
+	}
+}
+
+func TestURLParsed(t *testing.T) {
+	u := &url.URL{
+		Path:     "/search",
+		RawQuery: url.Values{"q": []string{"htmx go"}, "page": []string{"2"}}.Encode(),
+	}
+
+	headers, err := NewResponse().
+		PushURLParsed(u).
+		ReplaceURLParsed(u).
+		Headers()
+	if err != nil {
+		t.Errorf("an error occurred getting headers: %v", err)
+	}
+
+	want := "/search?page=2&q=htmx+go"
+	for _, k := range []string{HeaderPushURL, HeaderReplaceUrl} {
+		if got := headers[k]; got != want {
+			t.Errorf("wrong value for header %q. got=%q, want=%q", k, got, want)
+		}
+	}
+
+	headers, err = NewResponse().
+		PushURLParsed(nil).
+		ReplaceURLParsed(nil).
+		Headers()
+	if err != nil {
+		t.Errorf("an error occurred getting headers: %v", err)
+	}
+
+	for _, k := range []string{HeaderPushURL, HeaderReplaceUrl} {
+		if _, ok := headers[k]; ok {
+			t.Errorf("header %q should not be set for a nil URL", k)
+		}
+	}
+}
+
+func TestNamedStatusCodes(t *testing.T) {
+	testCases := []struct {
+		name       string
+		response   Response
+		statusCode int
+	}{
+		{
+			name:       "stop polling",
+			response:   NewResponse().StopPolling(),
+			statusCode: StatusStopPolling,
+		},
+		{
+			name:       "not modified",
+			response:   NewResponse().NotModified(),
+			statusCode: http.StatusNotModified,
+		},
+	}
+
+	for _, tc := range testCases {
+		w := newMockResponseWriter()
+		if err := tc.response.Write(w); err != nil {
+			t.Errorf("%s: an error occurred writing a response: %v", tc.name, err)
+		}
+		if w.statusCode != tc.statusCode {
+			t.Errorf("%s: wrong status code. want=%v, got=%v", tc.name, tc.statusCode, w.statusCode)
+		}
+	}
+}
+
+func TestTriggers(t *testing.T) {
+	r := NewResponse().
+		AddTrigger(Trigger("a"), Trigger("b")).
+		AddTriggerAfterSwap(TriggerDetail("c", "1"))
+
+	triggers, afterSettle, afterSwap := r.Triggers()
+
+	if len(triggers) != 2 || triggers[0] != Trigger("a") || triggers[1] != Trigger("b") {
+		t.Errorf("wrong triggers. got=%v", triggers)
+	}
+	if afterSettle != nil {
+		t.Errorf("expected no triggers after settle, got=%v", afterSettle)
+	}
+	if len(afterSwap) != 1 || afterSwap[0] != TriggerDetail("c", "1") {
+		t.Errorf("wrong triggers after swap. got=%v", afterSwap)
+	}
+
+	triggers[0] = Trigger("mutated")
+	if r.triggers[0] != Trigger("a") {
+		t.Errorf("modifying the returned triggers modified the response")
+	}
+}
+
+func TestToastTrigger(t *testing.T) {
+	result, err := triggersToString([]EventTrigger{ToastTrigger("info", "Here Is A Message")})
+	if err != nil {
+		t.Errorf("an error occurred marshalling triggers: %v", err)
+	}
+	if want := `{"showMessage":{"level":"info","message":"Here Is A Message"}}`; result != want {
+		t.Errorf(`got: "%v", want: "%v"`, result, want)
+	}
+
+	defer func(event string) { ToastEvent = event }(ToastEvent)
+	ToastEvent = "toast"
+
+	result, err = triggersToString([]EventTrigger{ToastTrigger("error", "Failed")})
+	if err != nil {
+		t.Errorf("an error occurred marshalling triggers: %v", err)
+	}
+	if want := `{"toast":{"level":"error","message":"Failed"}}`; result != want {
+		t.Errorf(`got: "%v", want: "%v"`, result, want)
+	}
+}
+
+func TestHeader(t *testing.T) {
+	w := newMockResponseWriter()
+
+	err := NewResponse().
+		Header("X-Custom", "first").
+		Header("X-Custom", "second").
+		Header("Cache-Control", "no-cache").
+		Retarget("#main").
+		Write(w)
+	if err != nil {
+		t.Errorf("an error occurred writing a response: %v", err)
+	}
+
+	expectedHeaders := map[string]string{
+		"X-Custom":      "second",
+		"Cache-Control": "no-cache",
+		HeaderRetarget:  "#main",
+	}
+
+	for k, v := range expectedHeaders {
+		if got := w.header.Get(k); got != v {
+			t.Errorf("wrong value for header %q. got=%q, want=%q", k, got, v)
+		}
+	}
+}
+
+func TestContentType(t *testing.T) {
+	testCases := []struct {
+		name        string
+		response    Response
+		contentType string
+	}{
+		{
+			name:        "custom",
+			response:    NewResponse().ContentType("text/plain"),
+			contentType: "text/plain",
+		},
+		{
+			name:        "html",
+			response:    NewResponse().ContentTypeHTML(),
+			contentType: "text/html; charset=utf-8",
+		},
+		{
+			name:        "json",
+			response:    NewResponse().ContentTypeJSON(),
+			contentType: "application/json",
+		},
+	}
+
+	for _, tc := range testCases {
+		w := newMockResponseWriter()
+		if err := tc.response.Write(w); err != nil {
+			t.Errorf("%s: an error occurred writing a response: %v", tc.name, err)
+		}
+		if got := w.header.Get("Content-Type"); got != tc.contentType {
+			t.Errorf(`%s: got: "%v", want: "%v"`, tc.name, got, tc.contentType)
+		}
+	}
+}
+
+func TestReswapShortcuts(t *testing.T) {
+	testCases := []struct {
+		name     string
+		response Response
+		reswap   string
+	}{
+		{name: "inner html", response: NewResponse().ReswapInnerHTML(), reswap: "innerHTML"},
+		{name: "outer html", response: NewResponse().ReswapOuterHTML(), reswap: "outerHTML"},
+		{name: "before begin", response: NewResponse().ReswapBeforeBegin(), reswap: "beforebegin"},
+		{name: "after begin", response: NewResponse().ReswapAfterBegin(), reswap: "afterbegin"},
+		{name: "before end", response: NewResponse().ReswapBeforeEnd(), reswap: "beforeend"},
+		{name: "after end", response: NewResponse().ReswapAfterEnd(), reswap: "afterend"},
+		{name: "delete", response: NewResponse().ReswapDelete(), reswap: "delete"},
+		{name: "none", response: NewResponse().ReswapNone(), reswap: "none"},
+		{name: "overridden", response: NewResponse().ReswapNone().Reswap(SwapOuterHTML), reswap: "outerHTML"},
+	}
+
+	for _, tc := range testCases {
+		if got := tc.response.headers[HeaderReswap]; got != tc.reswap {
+			t.Errorf(`%s: got: "%v", want: "%v"`, tc.name, got, tc.reswap)
+		}
+	}
+}
+
+func TestLocationTarget(t *testing.T) {
+	headers, err := NewResponse().LocationTarget("/messages", "#main").Headers()
+	if err != nil {
+		t.Errorf("an error occurred getting headers: %v", err)
+	}
+
+	if got, want := headers[HeaderLocation], `{"path":"/messages","target":"#main"}`; got != want {
+		t.Errorf("wrong value for header %q. got=%q, want=%q", HeaderLocation, got, want)
+	}
+}
+
+func TestLocationWithContext_EmptyPath(t *testing.T) {
+	r := NewResponse().LocationWithContext("", LocationContext{Target: "#main"})
+
+	if err := r.Err(); err == nil {
+		t.Errorf("expected an error for an empty path")
+	}
+	if _, ok := r.headers[HeaderLocation]; ok {
+		t.Errorf("header %q should not be set for an empty path", HeaderLocation)
+	}
+	if err := r.Write(newMockResponseWriter()); err == nil {
+		t.Errorf("expected Write to return an error for an empty path")
+	}
+
+	// A later valid call replaces the error
+	if err := r.LocationWithContext("/messages", LocationContext{}).Err(); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+}
+
+func TestTriggerValue(t *testing.T) {
+	testCases := []struct {
+		name    string
+		trigger EventTrigger
+		result  string
+	}{
+		{
+			name:    "number",
+			trigger: TriggerValue("count", 5),
+			result:  `{"count":5}`,
+		},
+		{
+			name:    "boolean",
+			trigger: TriggerValue("done", true),
+			result:  `{"done":true}`,
+		},
+		{
+			name:    "string",
+			trigger: TriggerValue("name", "joe"),
+			result:  `{"name":"joe"}`,
+		},
+		{
+			name:    "object",
+			trigger: TriggerObject("count", map[string]int{"value": 5}),
+			result:  `{"count":{"value":5}}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		result, err := triggersToString([]EventTrigger{tc.trigger})
+		if err != nil {
+			t.Errorf("%s: an error occurred marshalling triggers: %v", tc.name, err)
+		}
+		if result != tc.result {
+			t.Errorf(`%s: got: "%v", want: "%v"`, tc.name, result, tc.result)
+		}
+	}
+}
+
+func TestRefreshPage(t *testing.T) {
+	headers, err := NewResponse().RefreshPage().Headers()
+	if err != nil {
+		t.Errorf("an error occurred getting headers: %v", err)
+	}
+
+	if got := headers[HeaderRefresh]; got != "true" {
+		t.Errorf("wrong value for header %q. got=%q, want=%q", HeaderRefresh, got, "true")
+	}
+}
+
+func TestTriggerNamed(t *testing.T) {
+	type appEvent string
+	const contactAdded appEvent = "contactAdded"
+
+	testCases := []struct {
+		name  string
+		named EventTrigger
+		plain EventTrigger
+	}{
+		{
+			name:  "plain",
+			named: TriggerNamed(contactAdded),
+			plain: Trigger("contactAdded"),
+		},
+		{
+			name:  "detail",
+			named: TriggerDetailNamed(contactAdded, "Joe"),
+			plain: TriggerDetail("contactAdded", "Joe"),
+		},
+		{
+			name:  "object",
+			named: TriggerObjectNamed(contactAdded, map[string]string{"name": "Joe"}),
+			plain: TriggerObject("contactAdded", map[string]string{"name": "Joe"}),
+		},
+	}
+
+	for _, tc := range testCases {
+		named, err := triggersToString([]EventTrigger{tc.named})
+		if err != nil {
+			t.Errorf("%s: an error occurred marshalling triggers: %v", tc.name, err)
+		}
+		plain, err := triggersToString([]EventTrigger{tc.plain})
+		if err != nil {
+			t.Errorf("%s: an error occurred marshalling triggers: %v", tc.name, err)
+		}
+		if named != plain {
+			t.Errorf(`%s: got: "%v", want: "%v"`, tc.name, named, plain)
+		}
+	}
+}
+
+func TestClearTriggers(t *testing.T) {
+	if NewResponse().HasTriggers() {
+		t.Errorf("new response should not have triggers")
+	}
+
+	if res := NewResponse().ClearTriggers(); res.HasTriggers() {
+		t.Errorf("clearing a response without triggers should not add triggers")
+	}
+
+	base := NewResponse().
+		AddTrigger(Trigger("saved")).
+		AddTriggerAfterSettle(Trigger("settled")).
+		AddTriggerAfterSwap(Trigger("swapped"))
+
+	if !base.HasTriggers() {
+		t.Errorf("response with triggers should have triggers")
+	}
+
+	cleared := base.Clone().ClearTriggers()
+	if cleared.HasTriggers() {
+		t.Errorf("cleared response should not have triggers")
+	}
+
+	headers, err := cleared.Headers()
+	if err != nil {
+		t.Errorf("an error occurred writing a response: %v", err)
+	}
+	for _, k := range []string{HeaderTrigger, HeaderTriggerAfterSettle, HeaderTriggerAfterSwap} {
+		if v, ok := headers[k]; ok {
+			t.Errorf("header %q should not be set, got=%q", k, v)
+		}
+	}
+
+	cleared = base.ClearTriggers()
+	if cleared.HasTriggers() {
+		t.Errorf("cleared response should not have triggers")
+	}
+	if !base.HasTriggers() {
+		t.Errorf("clearing a copy should not remove the triggers of the original response")
+	}
+
+	if res := base.ClearTriggers().AddTriggerAfterSwap(Trigger("swapped")); !res.HasTriggers() {
+		t.Errorf("response should have triggers after adding one to a cleared response")
+	}
+}
+
+func TestPushURLRelative(t *testing.T) {
+	testCases := []struct {
+		name       string
+		currentURL string
+		ref        string
+		want       string
+	}{
+		{
+			name:       "sibling path",
+			currentURL: "https://example.com/contacts/1/view",
+			ref:        "edit",
+			want:       "/contacts/1/edit",
+		},
+		{
+			name:       "parent path",
+			currentURL: "https://example.com/contacts/1/view",
+			ref:        "../2/view",
+			want:       "/contacts/2/view",
+		},
+		{
+			name:       "query only",
+			currentURL: "https://example.com/contacts?page=1",
+			ref:        "?page=2",
+			want:       "/contacts?page=2",
+		},
+		{
+			name:       "absolute path",
+			currentURL: "https://example.com/contacts/1",
+			ref:        "/settings#profile",
+			want:       "/settings#profile",
+		},
+		{
+			name:       "other origin",
+			currentURL: "https://example.com/contacts",
+			ref:        "https://other.example.com/page",
+			want:       "https://other.example.com/page",
+		},
+		{
+			name: "no current URL",
+			ref:  "edit",
+			want: "edit",
+		},
+	}
+
+	for _, tc := range testCases {
+		headers := map[string]string{HeaderRequest: "true"}
+		if tc.currentURL != "" {
+			headers[HeaderCurrentURL] = tc.currentURL
+		}
+		req := newHTMXRequest(headers)
+
+		res := NewResponse().PushURLRelative(req, tc.ref)
+		if got := res.headers[HeaderPushURL]; got != tc.want {
+			t.Errorf(`%s: got: "%v", want: "%v"`, tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestErrorFragment(t *testing.T) {
+	w := newMockResponseWriter()
+
+	_, err := NewResponse().
+		ErrorFragment(http.StatusUnprocessableEntity, "#errors", SwapInnerHTML).
+		RenderHTML(w, "<p>Invalid email</p>")
+	if err != nil {
+		t.Errorf("an error occurred writing a response: %v", err)
+	}
+
+	if w.statusCode != http.StatusUnprocessableEntity {
+		t.Errorf("wrong status code. want=%v, got=%v", http.StatusUnprocessableEntity, w.statusCode)
+	}
+
+	want := map[string]string{
+		HeaderRetarget: "#errors",
+		HeaderReswap:   "innerHTML",
+	}
+	for k, v := range want {
+		if got := w.Header().Get(k); got != v {
+			t.Errorf("wrong value for header %q. got=%q, want=%q", k, got, v)
+		}
+	}
+}
+
+func TestConfigTrigger(t *testing.T) {
+	w := newMockResponseWriter()
+
+	err := NewResponse().
+		AddTrigger(ConfigTrigger(map[string]any{"timeout": 5000, "withCredentials": true})).
+		Write(w)
+	if err != nil {
+		t.Errorf("an error occurred writing a response: %v", err)
+	}
+
+	want := `{"htmx:config":{"timeout":5000,"withCredentials":true}}`
+	if got := w.Header().Get(HeaderTrigger); got != want {
+		t.Errorf("wrong value for header %q. got=%q, want=%q", HeaderTrigger, got, want)
+	}
+
+	defer func(event string) { ConfigEvent = event }(ConfigEvent)
+	ConfigEvent = "app:config"
+
+	result, err := triggersToString([]EventTrigger{ConfigTrigger(map[string]any{"timeout": 0})})
+	if err != nil {
+		t.Errorf("an error occurred marshalling triggers: %v", err)
+	}
+	if want := `{"app:config":{"timeout":0}}`; result != want {
+		t.Errorf(`got: "%v", want: "%v"`, result, want)
+	}
+}
+
+func TestCookie(t *testing.T) {
+	w := newMockResponseWriter()
+
+	err := NewResponse().
+		Cookie(&http.Cookie{Name: "session", Value: "abc", Path: "/"}).
+		Cookie(nil).
+		Cookie(&http.Cookie{Name: "theme", Value: "dark"}).
+		Redirect("/dashboard").
+		Write(w)
+	if err != nil {
+		t.Errorf("an error occurred writing a response: %v", err)
+	}
+
+	if got := w.Header().Get(HeaderRedirect); got != "/dashboard" {
+		t.Errorf("wrong value for header %q. got=%q, want=%q", HeaderRedirect, got, "/dashboard")
+	}
+
+	want := []string{"session=abc; Path=/", "theme=dark"}
+	got := w.Header().Values("Set-Cookie")
+	if len(got) != len(want) {
+		t.Fatalf("wrong number of cookies. got=%q, want=%q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("wrong value for cookie %d. got=%q, want=%q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestVaryOnHTMX(t *testing.T) {
+	testCases := []struct {
+		name   string
+		res    Response
+		vary   []string
+		result string
+	}{
+		{
+			name:   "no existing values",
+			res:    NewResponse().VaryOnHTMX(),
+			result: "HX-Request",
+		},
+		{
+			name:   "existing value on the writer",
+			res:    NewResponse().VaryOnHTMX(HeaderBoosted, HeaderTarget),
+			vary:   []string{"Accept"},
+			result: "Accept, HX-Request, HX-Boosted, HX-Target",
+		},
+		{
+			name:   "existing value on the response",
+			res:    NewResponse().Header("Vary", "Accept-Encoding").VaryOnHTMX(),
+			vary:   []string{"Accept"},
+			result: "Accept, Accept-Encoding, HX-Request",
+		},
+		{
+			name:   "no duplicates",
+			res:    NewResponse().VaryOnHTMX().NoCache().VaryOnHTMX("hx-request", HeaderBoosted),
+			vary:   []string{"Accept, HX-Boosted"},
+			result: "Accept, HX-Boosted, HX-Request",
+		},
+		{
+			name:   "wildcard",
+			res:    NewResponse().VaryOnHTMX(),
+			vary:   []string{"*"},
+			result: "*",
+		},
+	}
+
+	for _, tc := range testCases {
+		w := newMockResponseWriter()
+		for _, v := range tc.vary {
+			w.Header().Add("Vary", v)
+		}
+
+		if err := tc.res.Write(w); err != nil {
+			t.Errorf("%s: an error occurred writing a response: %v", tc.name, err)
+		}
+
+		if got := w.Header().Values("Vary"); len(got) != 1 || got[0] != tc.result {
+			t.Errorf(`%s: got: "%v", want: "%v"`, tc.name, got, tc.result)
+		}
+	}
+}
+
+func TestStopPollingTrigger(t *testing.T) {
+	w := newMockResponseWriter()
+
+	err := NewResponse().
+		Retarget("#progress").
+		StopPollingTrigger("jobFinished").
+		Write(w)
+	if err != nil {
+		t.Errorf("an error occurred writing a response: %v", err)
+	}
+
+	if w.statusCode != StatusStopPolling {
+		t.Errorf("wrong status code. want=%v, got=%v", StatusStopPolling, w.statusCode)
+	}
+
+	expectedHeaders := map[string]string{
+		HeaderTrigger:  "jobFinished",
+		HeaderRetarget: "#progress",
+	}
+	for k, v := range expectedHeaders {
+		if got := w.Header().Get(k); got != v {
+			t.Errorf("wrong value for header %q. got=%q, want=%q", k, got, v)
+		}
+	}
+}
+
+func TestCancelPolling(t *testing.T) {
+	w := newMockResponseWriter()
+
+	err := NewResponse().
+		Retarget("#progress").
+		CancelPolling().
+		Write(w)
+	if err != nil {
+		t.Errorf("an error occurred writing a response: %v", err)
+	}
+
+	if w.statusCode != StatusStopPolling {
+		t.Errorf("wrong status code. want=%v, got=%v", StatusStopPolling, w.statusCode)
+	}
+	if got := w.Header().Get(HeaderRetarget); got != "#progress" {
+		t.Errorf("wrong value for header %q. got=%q, want=%q", HeaderRetarget, got, "#progress")
+	}
+}
+
+func TestTriggerEncoder(t *testing.T) {
+	defer func(encoder func(any) ([]byte, error)) { TriggerEncoder = encoder }(TriggerEncoder)
+
+	TriggerEncoder = func(v any) ([]byte, error) {
+		var buf bytes.Buffer
+		enc := json.NewEncoder(&buf)
+		enc.SetEscapeHTML(false)
+		err := enc.Encode(v)
+		return buf.Bytes(), err
+	}
+
+	headers, err := NewResponse().
+		AddTrigger(TriggerDetail("showMessage", "<b>Saved</b> & done")).
+		LocationWithContext("/contacts", LocationContext{Target: "#a>b"}).
+		Headers()
+	if err != nil {
+		t.Errorf("an error occurred getting headers: %v", err)
+	}
+
+	expectedHeaders := map[string]string{
+		HeaderTrigger:  `{"showMessage":"<b>Saved</b> & done"}`,
+		HeaderLocation: `{"path":"/contacts","target":"#a>b"}`,
+	}
+	for k, v := range expectedHeaders {
+		if got := headers[k]; got != v {
+			t.Errorf("wrong value for header %q. got=%q, want=%q", k, got, v)
+		}
+	}
+
+	TriggerEncoder = func(v any) ([]byte, error) {
+		return nil, errors.New("encoder failed")
+	}
+
+	if err := NewResponse().AddTrigger(TriggerDetail("a", "b")).Write(newMockResponseWriter()); err == nil {
+		t.Errorf("expected an error from the trigger encoder")
+	}
+}
+
+func TestReswapModify(t *testing.T) {
+	transition := func(s SwapStrategy) SwapStrategy { return s.Transition(true) }
+
+	testCases := []struct {
+		name   string
+		res    Response
+		result string
+	}{
+		{
+			name:   "existing reswap",
+			res:    NewResponse().Reswap(SwapInnerHTML.Scroll(Top)).ReswapModify(transition),
+			result: "innerHTML scroll:top transition:true",
+		},
+		{
+			name:   "no reswap",
+			res:    NewResponse().ReswapModify(transition),
+			result: "transition:true",
+		},
+		{
+			name: "replaced modifier",
+			res: NewResponse().Reswap(SwapOuterHTML.Transition(false)).
+				ReswapModify(transition),
+			result: "outerHTML transition:true",
+		},
+	}
+
+	for _, tc := range testCases {
+		if got := tc.res.headers[HeaderReswap]; got != tc.result {
+			t.Errorf(`%s: got: "%v", want: "%v"`, tc.name, got, tc.result)
+		}
+	}
+
+	base := NewResponse().Reswap(SwapInnerHTML)
+	base.Clone().ReswapModify(transition)
+	if got := base.headers[HeaderReswap]; got != "innerHTML" {
+		t.Errorf(`modifying a clone changed the original: got: "%v", want: "%v"`, got, "innerHTML")
+	}
+}
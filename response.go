@@ -1,11 +1,17 @@
 package htmx
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"html/template"
+	"io"
 	"net/http"
+	"sort"
+	"strings"
+	"sync/atomic"
 )
 
 // Response contains HTMX headers to write to a response.
@@ -28,6 +34,37 @@ type Response struct {
 	// JSON marshalling might fail, so we need to keep track of this error
 	// to return when `Write` is called
 	locationWithContextErr []error
+
+	// Other errors from building the response, returned when `Write` is called
+	errs []error
+
+	// Callbacks to run after a successful write
+	onWritten []func()
+
+	// Callbacks to run after a failed write
+	onError []func(error)
+
+	// Cookies to set when writing
+	cookies []*http.Cookie
+
+	// Out-of-band fragments rendered after the main content
+	oob []template.HTML
+}
+
+// Whether responses ignore titles in swapped content by default, set with SetDefaultIgnoreTitle
+var defaultIgnoreTitle atomic.Bool
+
+// SetDefaultIgnoreTitle sets whether responses prevent HTMX from updating the page title
+// by default, so fragments that happen to contain a '<title>' tag don't overwrite it.
+//
+// When enabled, [htmx.Response.Reswap] adds the 'ignoreTitle:true' modifier to swap
+// strategies that don't set 'ignoreTitle' themselves. Use [SwapStrategy.IgnoreTitle]
+// to override it. Responses that never call Reswap don't set 'HX-Reswap', so the
+// 'hx-swap' attribute of the triggering element is kept as is.
+//
+// Disabled by default.
+func SetDefaultIgnoreTitle(ignore bool) {
+	defaultIgnoreTitle.Store(ignore)
 }
 
 // NewResponse returns a new HTMX response header writer.
@@ -40,22 +77,173 @@ func NewResponse() Response {
 	}
 }
 
+// Redirect returns a new HTMX response that does a client-side redirect to a new location.
+//
+// This is the same as htmx.NewResponse().Redirect(path).
+//
+// Example:
+//
+//	htmx.Redirect("/login").MustWrite(w)
+func Redirect(path string) Response {
+	return NewResponse().Redirect(path)
+}
+
+// Refresh returns a new HTMX response that makes the client do a full refresh of the page.
+//
+// This is the same as htmx.NewResponse().RefreshPage().
+func Refresh() Response {
+	return NewResponse().RefreshPage()
+}
+
+// StopPolling returns a new HTMX response that makes the client stop polling,
+// with the status code 286.
+//
+// This is the same as htmx.NewResponse().StopPolling().
+func StopPolling() Response {
+	return NewResponse().StopPolling()
+}
+
 // Clone returns a clone of this HTMX response writer, preventing any mutation
 // on the original response.
+//
+// The clone has copies of the headers, triggers, status code, errors, cookies,
+// out-of-band fragments and callbacks of the original response.
 func (r Response) Clone() Response {
+	n := Response{
+		headers: make(map[string]string, len(r.headers)),
+	}
+
+	for k, v := range r.headers {
+		n.headers[k] = v
+	}
+
+	n.statusCode = r.statusCode
+
+	n.triggers = copyTriggers(r.triggers)
+	n.triggersAfterSettle = copyTriggers(r.triggersAfterSettle)
+	n.triggersAfterSwap = copyTriggers(r.triggersAfterSwap)
+
+	n.locationWithContextErr = append([]error{}, r.locationWithContextErr...)
+	n.errs = append([]error{}, r.errs...)
+	n.onWritten = append([]func(){}, r.onWritten...)
+	n.onError = append([]func(error){}, r.onError...)
+	n.cookies = append([]*http.Cookie{}, r.cookies...)
+	n.oob = append([]template.HTML{}, r.oob...)
+
+	return n
+}
+
+// Clear returns a new empty response, discarding all headers, triggers,
+// the status code, and accumulated errors, as if created with [htmx.NewResponse].
+//
+// Clear does not modify the response it is called on.
+func (r Response) Clear() Response {
+	return NewResponse()
+}
+
+// Merge returns a new response combining this response with another,
+// without modifying either of them.
+//
+// Precedence when both responses set something:
+//
+//   - Headers from other overwrite headers from this response.
+//   - Triggers of other are added after the triggers of this response,
+//     for 'HX-Trigger', 'HX-Trigger-After-Settle' and 'HX-Trigger-After-Swap'.
+//   - The status code of other is used only if it is set.
+//   - Errors, cookies and [htmx.Response.OnWritten] callbacks of both responses are kept,
+//     this response's first.
+func (r Response) Merge(other Response) Response {
 	n := NewResponse()
 
 	for k, v := range r.headers {
 		n.headers[k] = v
 	}
+	for k, v := range other.headers {
+		n.headers[k] = v
+	}
+
+	n.statusCode = r.statusCode
+	if other.statusCode != 0 {
+		n.statusCode = other.statusCode
+	}
+
+	n.triggers = mergeTriggers(r.triggers, other.triggers)
+	n.triggersAfterSettle = mergeTriggers(r.triggersAfterSettle, other.triggersAfterSettle)
+	n.triggersAfterSwap = mergeTriggers(r.triggersAfterSwap, other.triggersAfterSwap)
+
+	n.locationWithContextErr = append(append([]error{}, r.locationWithContextErr...), other.locationWithContextErr...)
+	n.errs = append(append([]error{}, r.errs...), other.errs...)
+	n.onWritten = append(append([]func(){}, r.onWritten...), other.onWritten...)
+	n.onError = append(append([]func(error){}, r.onError...), other.onError...)
+	n.cookies = append(append([]*http.Cookie{}, r.cookies...), other.cookies...)
+	n.oob = append(append([]template.HTML{}, r.oob...), other.oob...)
 
 	return n
 }
 
+// mergeTriggers concatenates two trigger slices into a new slice.
+//
+// Returns nil if both are nil, so the header is not written.
+func mergeTriggers(a, b []EventTrigger) []EventTrigger {
+	if a == nil && b == nil {
+		return nil
+	}
+	return append(append(make([]EventTrigger, 0, len(a)+len(b)), a...), b...)
+}
+
 // Write applies the defined HTMX headers to a given response writer.
+//
+// The status code, if set, is written on every call to a plain response writer.
+// To write responses more than once to the same writer, e.g. in a middleware stack,
+// use a writer from [htmx.Response.Wrap]: the status code is skipped once that writer
+// has written one, so it is written only once per writer.
+//
+// If a hook is set with [htmx.SetMetricsHook], it is called with the result of the write.
 func (r Response) Write(w http.ResponseWriter) error {
-	if len(r.locationWithContextErr) > 0 {
-		return errors.Join(r.locationWithContextErr...)
+	err := r.write(w)
+	if err != nil {
+		return r.failed(err)
+	}
+
+	r.written()
+	return nil
+}
+
+// WriteAndFlush applies the defined HTMX headers to a given response writer and
+// sends them to the client immediately, before any body is written.
+//
+// This is useful for streaming or long-lived responses. If no status code is set,
+// 200 OK is written. If the response writer does not implement [http.Flusher],
+// this behaves like [htmx.Response.Write].
+func (r Response) WriteAndFlush(w http.ResponseWriter) error {
+	err := r.write(w)
+	if err != nil {
+		return r.failed(err)
+	}
+
+	if flusher, ok := w.(http.Flusher); ok {
+		if r.statusCode == 0 {
+			w.WriteHeader(http.StatusOK)
+		}
+		flusher.Flush()
+	}
+
+	r.written()
+	return nil
+}
+
+// Internal method for Write that does not run the [htmx.Response.OnWritten] callbacks,
+// so methods that also write a body can run them afterwards
+func (r Response) write(w http.ResponseWriter) error {
+	err := r.writeHeaders(w)
+	r.recordMetrics(err)
+	return err
+}
+
+// Internal method for write
+func (r Response) writeHeaders(w http.ResponseWriter) error {
+	if err := r.Err(); err != nil {
+		return err
 	}
 
 	headers, err := r.Headers()
@@ -65,43 +253,306 @@ func (r Response) Write(w http.ResponseWriter) error {
 
 	headerWriter := w.Header()
 	for k, v := range headers {
+		if k == headerVary {
+			// Keep the 'Vary' values already set on the response writer
+			v = appendVary(strings.Join(headerWriter.Values(headerVary), ", "), v)
+		}
 		headerWriter.Set(k, v)
 	}
 
+	for _, c := range r.cookies {
+		http.SetCookie(w, c)
+	}
+
 	// Status code needs to be written after the other headers
 	// so the other headers can be written
-	if r.statusCode != 0 {
+	if r.statusCode != 0 && !wroteHeader(w) {
 		w.WriteHeader(r.statusCode)
 	}
 
 	return nil
 }
 
+// Err returns the errors accumulated while building the response, such as from
+// [htmx.Response.LocationWithContext], joined into one error.
+//
+// These errors are returned by [htmx.Response.Write], but Err lets you check them
+// without writing the response. Returns nil if there are no errors.
+func (r Response) Err() error {
+	errs := make([]error, 0, len(r.locationWithContextErr)+len(r.errs))
+	errs = append(errs, r.locationWithContextErr...)
+	errs = append(errs, r.errs...)
+	return errors.Join(errs...)
+}
+
 // RenderHTML renders an HTML document fragment along with the defined HTMX headers.
+//
+// Out-of-band fragments added with [htmx.Response.AddOOB] are rendered after the fragment.
 func (r Response) RenderHTML(w http.ResponseWriter, html template.HTML) (int, error) {
-	err := r.Write(w)
+	err := r.write(w)
+	if err != nil {
+		return 0, r.failed(err)
+	}
+
+	for _, fragment := range r.oob {
+		html += fragment
+	}
+
+	n, err := w.Write([]byte(html))
+	if err != nil {
+		return n, r.failed(err)
+	}
+
+	r.written()
+	return n, nil
+}
+
+// WriteJSON writes a JSON-encoded value as the response body along with the defined HTMX headers,
+// e.g. for the 'client-side-templates' extension.
+//
+// Sets the 'Content-Type' header to 'application/json'. The value is marshalled before
+// anything is written, so a marshalling error leaves the response untouched.
+func (r Response) WriteJSON(w http.ResponseWriter, v any) error {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return r.failed(fmt.Errorf("marshalling JSON body failed: %w", err))
+	}
+
+	if err := r.Err(); err != nil {
+		return r.failed(err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	err = r.write(w)
+	if err != nil {
+		return r.failed(err)
+	}
+
+	_, err = w.Write(body)
+	if err != nil {
+		return r.failed(err)
+	}
+
+	r.written()
+	return nil
+}
+
+// RenderHTMLOOB renders a main HTML document fragment followed by out-of-band fragments,
+// along with the defined HTMX headers.
+//
+// Use [htmx.OOB] to build the out-of-band fragments.
+// Without out-of-band fragments, this is the same as [htmx.Response.RenderHTML].
+func (r Response) RenderHTMLOOB(w http.ResponseWriter, main template.HTML, oob ...template.HTML) (int, error) {
+	for _, fragment := range oob {
+		main += fragment
+	}
+	return r.RenderHTML(w, main)
+}
+
+// PollResult responds to a polling request, only rendering content when it has changed.
+//
+// If changed is false, render is not called and the response is written with
+// 'HX-Reswap: none', so the client skips the swap, and a 204 No Content status
+// unless a status code is already set, e.g. by [htmx.Response.StopPollingIf].
+// Otherwise, the HTML returned by render is rendered with [htmx.Response.RenderHTML].
+func (r Response) PollResult(w http.ResponseWriter, changed bool, render func() (template.HTML, error)) (int, error) {
+	if !changed {
+		res := r.Clone()
+		if res.statusCode == 0 {
+			res = res.StatusCode(http.StatusNoContent)
+		}
+		return 0, res.Reswap(SwapNone).Write(w)
+	}
+
+	html, err := render()
 	if err != nil {
 		return 0, err
 	}
 
-	return w.Write([]byte(html))
+	return r.RenderHTML(w, html)
 }
 
 // RenderTempl renders a Templ component along with the defined HTMX headers.
-func (r Response) RenderTempl(ctx context.Context, w http.ResponseWriter, c templComponent) error {
-	err := r.Write(w)
+//
+// Out-of-band fragments added with [htmx.Response.AddOOB] are rendered after the component.
+func (r Response) RenderTempl(ctx context.Context, w http.ResponseWriter, c TemplComponent) error {
+	err := r.write(w)
 	if err != nil {
-		return err
+		return r.failed(err)
 	}
 
 	err = c.Render(ctx, w)
 	if err != nil {
-		return err
+		return r.failed(err)
+	}
+
+	err = r.writeOOB(w)
+	if err != nil {
+		return r.failed(err)
+	}
+
+	r.written()
+	return nil
+}
+
+// RenderTemplReq renders a Templ component along with the defined HTMX headers,
+// using the context of the given request.
+//
+// This is the same as [htmx.Response.RenderTempl] with req.Context().
+func (r Response) RenderTemplReq(req *http.Request, w http.ResponseWriter, c TemplComponent) error {
+	return r.RenderTempl(req.Context(), w, c)
+}
+
+// RenderTemplFull renders the fragment component for HTMX requests and the full page
+// component otherwise, along with the defined HTMX headers in both cases.
+//
+// If boostedAsFull is true, requests made via an element using 'hx-boost' also get
+// the full page, since boosted requests usually swap the whole body.
+func (r Response) RenderTemplFull(ctx context.Context, w http.ResponseWriter, req *http.Request, fragment, full TemplComponent, boostedAsFull bool) error {
+	if ShouldRenderFullPageOpt(req, boostedAsFull) {
+		return r.RenderTempl(ctx, w, full)
+	}
+	return r.RenderTempl(ctx, w, fragment)
+}
+
+// RenderTemplCounted renders a Templ component along with the defined HTMX headers,
+// returning the number of bytes written by the component.
+//
+// Out-of-band fragments added with [htmx.Response.AddOOB] are rendered after the component
+// and included in the count.
+//
+// If the headers fail to write, the component is not rendered.
+func (r Response) RenderTemplCounted(ctx context.Context, w http.ResponseWriter, c TemplComponent) (int, error) {
+	err := r.write(w)
+	if err != nil {
+		return 0, r.failed(err)
+	}
+
+	cw := &countingWriter{w: w}
+	err = c.Render(ctx, cw)
+	if err != nil {
+		return cw.n, r.failed(err)
+	}
+
+	err = r.writeOOB(cw)
+	if err != nil {
+		return cw.n, r.failed(err)
+	}
+
+	r.written()
+	return cw.n, nil
+}
+
+// countingWriter counts the bytes written to an underlying writer.
+type countingWriter struct {
+	w io.Writer
+	n int
+}
+
+func (cw *countingWriter) Write(b []byte) (int, error) {
+	n, err := cw.w.Write(b)
+	cw.n += n
+	return n, err
+}
+
+// RenderTemplTo renders a Templ component into the element found by the given CSS selector,
+// along with the defined HTMX headers.
+//
+// Under the hood this uses [htmx.Response.Retarget] and [htmx.Response.RenderTempl]
+// on a clone, so the receiver is left unchanged.
+func (r Response) RenderTemplTo(ctx context.Context, w http.ResponseWriter, cssSelector string, c TemplComponent) error {
+	return r.Clone().Retarget(cssSelector).RenderTempl(ctx, w, c)
+}
+
+// RenderTemplNotifyOnError renders a Templ component along with the defined HTMX headers.
+// If rendering fails, the client is notified through an event instead of receiving a partial body.
+//
+// The component is rendered into a buffer first. On failure, nothing from the component is written;
+// the response is sent with a 500 Internal Server Error status, 'HX-Reswap: none', and an 'HX-Trigger'
+// for errorEvent so a client-side listener can surface the error (e.g. with a toast).
+// The render error is still returned for logging.
+//
+// Out-of-band fragments added with [htmx.Response.AddOOB] are rendered after the component
+// on success, and are not written on failure.
+func (r Response) RenderTemplNotifyOnError(ctx context.Context, w http.ResponseWriter, c TemplComponent, errorEvent string) error {
+	var buf bytes.Buffer
+
+	renderErr := c.Render(ctx, &buf)
+	if renderErr != nil {
+		err := r.Clone().
+			StatusCode(http.StatusInternalServerError).
+			Reswap(SwapNone).
+			AddTrigger(Trigger(errorEvent)).
+			write(w)
+		return r.failed(errors.Join(renderErr, err))
 	}
 
+	err := r.write(w)
+	if err != nil {
+		return r.failed(err)
+	}
+
+	_, err = buf.WriteTo(w)
+	if err != nil {
+		return r.failed(err)
+	}
+
+	err = r.writeOOB(w)
+	if err != nil {
+		return r.failed(err)
+	}
+
+	r.written()
+	return nil
+}
+
+// writeOOB writes the out-of-band fragments added with AddOOB.
+func (r Response) writeOOB(w io.Writer) error {
+	for _, fragment := range r.oob {
+		_, err := io.WriteString(w, string(fragment))
+		if err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+// OnWritten registers a callback to run after the response is successfully written
+// by [htmx.Response.Write] or one of the render methods, e.g. to mark a notification as delivered.
+//
+// This can be called multiple times; callbacks run in the order they were registered.
+// Callbacks do not run if writing the response fails.
+func (r Response) OnWritten(fn func()) Response {
+	r.onWritten = append(r.onWritten[:len(r.onWritten):len(r.onWritten)], fn)
+	return r
+}
+
+// written runs the OnWritten callbacks.
+func (r Response) written() {
+	for _, fn := range r.onWritten {
+		fn()
+	}
+}
+
+// OnError registers a callback to run with the error when writing the response fails
+// with [htmx.Response.Write] or one of the render methods, e.g. to log the error.
+//
+// The callback is called once per failed write, before the error is returned.
+// This can be called multiple times; callbacks run in the order they were registered.
+func (r Response) OnError(fn func(error)) Response {
+	r.onError = append(r.onError[:len(r.onError):len(r.onError)], fn)
+	return r
+}
+
+// failed runs the OnError callbacks with err and returns it.
+func (r Response) failed(err error) error {
+	for _, fn := range r.onError {
+		fn(err)
+	}
+	return err
+}
+
 // MustWrite applies the defined HTMX headers to a given response writer, otherwise it panics.
 //
 // Under the hood this uses [Response.Write].
@@ -125,7 +576,7 @@ func (r Response) MustRenderHTML(w http.ResponseWriter, html template.HTML) {
 // MustRenderTempl renders a Templ component along with the defined HTMX headers, otherwise it panics.
 //
 // Under the hood this uses [Response.RenderTempl].
-func (r Response) MustRenderTempl(ctx context.Context, w http.ResponseWriter, c templComponent) {
+func (r Response) MustRenderTempl(ctx context.Context, w http.ResponseWriter, c TemplComponent) {
 	err := r.RenderTempl(ctx, w, c)
 	if err != nil {
 		panic(err)
@@ -134,10 +585,16 @@ func (r Response) MustRenderTempl(ctx context.Context, w http.ResponseWriter, c
 
 // Headers returns a copied map of the headers. Any modifications to the
 // returned headers will not affect the headers in this struct.
+//
+// Returns an error if a trigger can't be marshalled, or if a header value is
+// longer than [htmx.MaxHeaderBytes].
 func (r Response) Headers() (map[string]string, error) {
 	m := make(map[string]string)
 
 	for k, v := range r.headers {
+		if err := checkHeaderSize(k, v); err != nil {
+			return nil, err
+		}
 		m[k] = v
 	}
 
@@ -146,6 +603,10 @@ func (r Response) Headers() (map[string]string, error) {
 		if err != nil {
 			return nil, fmt.Errorf("marshalling triggers failed: %w", err)
 		}
+		triggers, err = fitTriggers(HeaderTrigger, r.triggers, triggers)
+		if err != nil {
+			return nil, err
+		}
 		m[HeaderTrigger] = triggers
 	}
 
@@ -154,6 +615,10 @@ func (r Response) Headers() (map[string]string, error) {
 		if err != nil {
 			return nil, fmt.Errorf("marshalling triggers after settle failed: %w", err)
 		}
+		triggers, err = fitTriggers(HeaderTriggerAfterSettle, r.triggersAfterSettle, triggers)
+		if err != nil {
+			return nil, err
+		}
 		m[HeaderTriggerAfterSettle] = triggers
 	}
 
@@ -162,8 +627,124 @@ func (r Response) Headers() (map[string]string, error) {
 		if err != nil {
 			return nil, fmt.Errorf("marshalling triggers after swap failed: %w", err)
 		}
+		triggers, err = fitTriggers(HeaderTriggerAfterSwap, r.triggersAfterSwap, triggers)
+		if err != nil {
+			return nil, err
+		}
 		m[HeaderTriggerAfterSwap] = triggers
 	}
 
 	return m, nil
 }
+
+// RawHeaders returns a copied map of the headers set directly on the response,
+// without the trigger headers, so it never fails.
+//
+// This excludes 'HX-Trigger', 'HX-Trigger-After-Settle' and 'HX-Trigger-After-Swap',
+// which is useful for inspecting a response when [htmx.Response.Headers] fails
+// because a trigger can't be marshalled.
+func (r Response) RawHeaders() map[string]string {
+	m := make(map[string]string, len(r.headers))
+	for k, v := range r.headers {
+		m[k] = v
+	}
+	return m
+}
+
+// triggerMarshalError is shown by [htmx.Response.String] in place of
+// trigger headers that can't be marshalled.
+const triggerMarshalError = "<trigger marshal error>"
+
+// String returns a readable multi-line summary of the response,
+// with the status code followed by each header in sorted order.
+//
+// Trigger headers that can't be marshalled are shown as '<trigger marshal error>'.
+//
+// Implements [fmt.Stringer].
+func (r Response) String() string {
+	m := r.RawHeaders()
+
+	triggerHeaders := []struct {
+		key      string
+		triggers []EventTrigger
+	}{
+		{HeaderTrigger, r.triggers},
+		{HeaderTriggerAfterSettle, r.triggersAfterSettle},
+		{HeaderTriggerAfterSwap, r.triggersAfterSwap},
+	}
+	for _, h := range triggerHeaders {
+		if h.triggers == nil {
+			continue
+		}
+		value, err := triggersToString(h.triggers)
+		if err != nil {
+			value = triggerMarshalError
+		}
+		m[h.key] = value
+	}
+
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	b.WriteString("htmx.Response\n")
+	if r.statusCode != 0 {
+		fmt.Fprintf(&b, "  Status: %d\n", r.statusCode)
+	} else {
+		b.WriteString("  Status: (unset)\n")
+	}
+	for _, k := range keys {
+		fmt.Fprintf(&b, "  %s: %s\n", k, m[k])
+	}
+	return b.String()
+}
+
+// Validate reports obvious misconfigurations of the response headers.
+//
+// Validate cannot inspect the response body, so it only detects combinations of
+// headers that contradict each other:
+//
+//   - 'HX-Reselect' is set, but 'HX-Reswap' is 'none' or 'delete', which never swaps
+//     in the selected content.
+//   - The 'HX-Reswap' swap strategy has conflicting modifiers, see [SwapStrategy.Validate].
+//   - Both 'HX-Redirect' and 'HX-Location' are set, so it is ambiguous where the client goes.
+//   - 'HX-Refresh' is 'true' along with 'HX-Redirect' or 'HX-Location', so the client
+//     does both a full refresh and a redirect.
+//
+// All conflicts found are returned as one joined error.
+func (r Response) Validate() error {
+	var errs []error
+
+	if _, ok := r.headers[HeaderReselect]; ok {
+		switch style := SwapStrategy(r.headers[HeaderReswap]).style(); style {
+		case SwapNone, SwapDelete:
+			errs = append(errs, fmt.Errorf("'%s' is set, but '%s' is %q which does not swap in content",
+				HeaderReselect, HeaderReswap, style))
+		}
+	}
+
+	_, hasRedirect := r.headers[HeaderRedirect]
+	_, hasLocation := r.headers[HeaderLocation]
+
+	if hasRedirect && hasLocation {
+		errs = append(errs, fmt.Errorf("both '%s' and '%s' are set", HeaderRedirect, HeaderLocation))
+	}
+
+	if r.headers[HeaderRefresh] == trueString {
+		if hasRedirect {
+			errs = append(errs, fmt.Errorf("'%s' is true, but '%s' is also set", HeaderRefresh, HeaderRedirect))
+		}
+		if hasLocation {
+			errs = append(errs, fmt.Errorf("'%s' is true, but '%s' is also set", HeaderRefresh, HeaderLocation))
+		}
+	}
+
+	if err := SwapStrategy(r.headers[HeaderReswap]).Validate(); err != nil {
+		errs = append(errs, fmt.Errorf("'%s' is invalid: %w", HeaderReswap, err))
+	}
+
+	return errors.Join(errs...)
+}
@@ -1,9 +1,16 @@
 package htmx
 
 import (
+	"context"
+	"errors"
 	"html/template"
+	"io"
 	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 )
 
 func TestWrite(t *testing.T) {
@@ -49,6 +56,232 @@ func TestWrite(t *testing.T) {
 	}
 }
 
+func TestSetDefaultIgnoreTitle(t *testing.T) {
+	SetDefaultIgnoreTitle(true)
+	defer SetDefaultIgnoreTitle(false)
+
+	testCases := []struct {
+		name     string
+		response Response
+		reswap   string
+	}{
+		{
+			name:     "reswap",
+			response: NewResponse().Reswap(SwapOuterHTML.Transition(true)),
+			reswap:   "outerHTML transition:true ignoreTitle:true",
+		},
+		{
+			name:     "overridden",
+			response: NewResponse().Reswap(SwapOuterHTML.IgnoreTitle(false)),
+			reswap:   "outerHTML ignoreTitle:false",
+		},
+	}
+
+	for _, tc := range testCases {
+		if got := tc.response.headers[HeaderReswap]; got != tc.reswap {
+			t.Errorf(`%s: got: "%v", want: "%v"`, tc.name, got, tc.reswap)
+		}
+	}
+
+	if _, ok := NewResponse().headers[HeaderReswap]; ok {
+		t.Errorf("header %q should only be set by Reswap", HeaderReswap)
+	}
+}
+
+func TestClear(t *testing.T) {
+	r := NewResponse().
+		StatusCode(http.StatusCreated).
+		Retarget("#main").
+		AddTrigger(Trigger("a"))
+	r.locationWithContextErr = []error{errors.New("marshalling failed")}
+
+	cleared := r.Clear()
+
+	if err := cleared.Err(); err != nil {
+		t.Errorf("expected no errors, got %v", err)
+	}
+
+	headers, err := cleared.Headers()
+	if err != nil {
+		t.Errorf("an error occurred getting headers: %v", err)
+	}
+	if len(headers) != 0 {
+		t.Errorf("expected no headers, got %v", headers)
+	}
+	if cleared.statusCode != 0 {
+		t.Errorf("expected no status code, got %v", cleared.statusCode)
+	}
+
+	if got := r.headers[HeaderRetarget]; got != "#main" {
+		t.Errorf("clearing modified the original response. got=%q, want=%q", got, "#main")
+	}
+}
+
+func TestClone(t *testing.T) {
+	r := NewResponse().
+		StatusCode(http.StatusCreated).
+		Retarget("#main").
+		AddTrigger(Trigger("a")).
+		AddTriggerAfterSettle(TriggerDetail("b", "settled")).
+		AddTriggerAfterSwap(Trigger("c"))
+	r.locationWithContextErr = []error{errors.New("marshalling failed")}
+
+	clone := r.Clone()
+
+	if clone.statusCode != http.StatusCreated {
+		t.Errorf("wrong status code. want=%v, got=%v", http.StatusCreated, clone.statusCode)
+	}
+	if clone.Err() == nil {
+		t.Errorf("expected the errors of the original response to be kept")
+	}
+
+	want, err := r.Headers()
+	if err != nil {
+		t.Errorf("an error occurred getting headers: %v", err)
+	}
+	got, err := clone.Headers()
+	if err != nil {
+		t.Errorf("an error occurred getting headers: %v", err)
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("wrong value for header %q. got=%q, want=%q", k, got[k], v)
+		}
+	}
+
+	clone = clone.
+		Retarget("#other").
+		AddTrigger(Trigger("d")).
+		RemoveTriggerAfterSwap("c")
+
+	if got := r.headers[HeaderRetarget]; got != "#main" {
+		t.Errorf("modifying the clone modified the original response. got=%q, want=%q", got, "#main")
+	}
+	if triggers, _, afterSwap := r.Triggers(); len(triggers) != 1 || len(afterSwap) != 1 {
+		t.Errorf("modifying the clone modified the triggers of the original response. got=%v, %v", triggers, afterSwap)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	swapper := NewResponse().
+		Reswap(SwapBeforeEnd).
+		Retarget("#list").
+		AddTrigger(Trigger("first"))
+	notifier := NewResponse().
+		Retarget("#messages").
+		StatusCode(http.StatusCreated).
+		AddTrigger(Trigger("second")).
+		AddTriggerAfterSwap(Trigger("swapped"))
+
+	merged := swapper.Merge(notifier)
+
+	headers, err := merged.Headers()
+	if err != nil {
+		t.Errorf("an error occurred getting headers: %v", err)
+	}
+
+	expectedHeaders := map[string]string{
+		HeaderReswap:           "beforeend",
+		HeaderRetarget:         "#messages",
+		HeaderTrigger:          "first, second",
+		HeaderTriggerAfterSwap: "swapped",
+	}
+
+	for k, v := range expectedHeaders {
+		if got := headers[k]; got != v {
+			t.Errorf("wrong value for header %q. got=%q, want=%q", k, got, v)
+		}
+	}
+
+	if _, ok := headers[HeaderTriggerAfterSettle]; ok {
+		t.Errorf("header %q should not be set", HeaderTriggerAfterSettle)
+	}
+
+	if merged.statusCode != http.StatusCreated {
+		t.Errorf("wrong status code. want=%v, got=%v", http.StatusCreated, merged.statusCode)
+	}
+
+	if got := notifier.Merge(NewResponse()).statusCode; got != http.StatusCreated {
+		t.Errorf("unset status code should not overwrite. want=%v, got=%v", http.StatusCreated, got)
+	}
+
+	if got := swapper.headers[HeaderRetarget]; got != "#list" {
+		t.Errorf("merging modified the original response. got=%q, want=%q", got, "#list")
+	}
+
+	withErr := NewResponse()
+	withErr.locationWithContextErr = []error{errors.New("marshalling failed")}
+	if err := NewResponse().Merge(withErr).Err(); err == nil {
+		t.Errorf("expected errors to be carried over")
+	}
+}
+
+func TestValidate_JoinsErrors(t *testing.T) {
+	err := NewResponse().
+		Refresh(true).
+		Redirect("/a").
+		Location("/b").
+		Validate()
+
+	joined, ok := err.(interface{ Unwrap() []error })
+	if !ok {
+		t.Fatalf("expected a joined error, got %v", err)
+	}
+	if got := len(joined.Unwrap()); got != 3 {
+		t.Errorf("wrong number of errors. got=%v, want=%v: %v", got, 3, err)
+	}
+}
+
+func TestWrite_SharedResponse(t *testing.T) {
+	base := NewResponse().StatusCode(http.StatusNotFound)
+
+	for i := 0; i < 2; i++ {
+		w := newMockResponseWriter()
+		if err := base.Retarget("#errors").Write(w); err != nil {
+			t.Errorf("an error occurred writing a response: %v", err)
+		}
+		if w.statusCode != http.StatusNotFound {
+			t.Errorf("write %d: wrong status code. want=%v, got=%v", i, http.StatusNotFound, w.statusCode)
+		}
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w := newMockResponseWriter()
+			if err := base.Write(w); err != nil {
+				t.Errorf("an error occurred writing a response: %v", err)
+			}
+			if w.statusCode != http.StatusNotFound {
+				t.Errorf("wrong status code. want=%v, got=%v", http.StatusNotFound, w.statusCode)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestRawHeaders(t *testing.T) {
+	r := NewResponse().
+		Retarget("#main").
+		AddTrigger(TriggerObject("bad", make(chan int)))
+
+	if _, err := r.Headers(); err == nil {
+		t.Errorf("expected an error getting headers with an unserializable trigger")
+	}
+
+	headers := r.RawHeaders()
+	if len(headers) != 1 || headers[HeaderRetarget] != "#main" {
+		t.Errorf("wrong raw headers. got=%v", headers)
+	}
+
+	headers[HeaderRetarget] = "#mutated"
+	if got := r.headers[HeaderRetarget]; got != "#main" {
+		t.Errorf("modifying the raw headers modified the response. got=%q", got)
+	}
+}
+
 func TestRenderHTML(t *testing.T) {
 	text := `hello world!`
 
@@ -76,6 +309,408 @@ func TestMustRenderHTML(t *testing.T) {
 	NewResponse().MustRenderHTML(w, template.HTML(text))
 }
 
+func TestWriteJSON(t *testing.T) {
+	w := newMockResponseWriter()
+
+	err := NewResponse().
+		StatusCode(http.StatusCreated).
+		AddTrigger(Trigger("contactAdded")).
+		WriteJSON(w, map[string]string{"name": "Joe"})
+	if err != nil {
+		t.Errorf("an error occurred writing JSON: %v", err)
+	}
+
+	if w.statusCode != http.StatusCreated {
+		t.Errorf("wrong status code. want=%v, got=%v", http.StatusCreated, w.statusCode)
+	}
+
+	expectedHeaders := map[string]string{
+		"Content-Type": "application/json",
+		HeaderTrigger:  "contactAdded",
+	}
+
+	for k, v := range expectedHeaders {
+		if got := w.header.Get(k); got != v {
+			t.Errorf("wrong value for header %q. got=%q, want=%q", k, got, v)
+		}
+	}
+
+	if want := `{"name":"Joe"}`; string(w.body) != want {
+		t.Errorf("wrong response body. got=%q, want=%q", string(w.body), want)
+	}
+
+	// Marshalling errors don't write anything
+	w = newMockResponseWriter()
+	if err := NewResponse().StatusCode(http.StatusCreated).WriteJSON(w, make(chan int)); err == nil {
+		t.Errorf("expected an error marshalling an unserializable value")
+	}
+	if w.statusCode != 0 || len(w.body) != 0 || len(w.header) != 0 {
+		t.Errorf("expected nothing to be written. status=%v, body=%q, headers=%v", w.statusCode, w.body, w.header)
+	}
+}
+
+func TestRenderTemplFull(t *testing.T) {
+	plain := newHTMXRequest(nil)
+	htmxRequest := newHTMXRequest(map[string]string{HeaderRequest: "true"})
+	boosted := newHTMXRequest(map[string]string{HeaderRequest: "true", HeaderBoosted: "true"})
+
+	testCases := []struct {
+		name          string
+		req           *http.Request
+		boostedAsFull bool
+		body          string
+	}{
+		{name: "non-HTMX request", req: plain, body: "full"},
+		{name: "HTMX request", req: htmxRequest, body: "fragment"},
+		{name: "boosted request", req: boosted, body: "fragment"},
+		{name: "boosted request as full page", req: boosted, boostedAsFull: true, body: "full"},
+	}
+
+	for _, tc := range testCases {
+		w := newMockResponseWriter()
+
+		err := NewResponse().
+			Retarget("#main").
+			RenderTemplFull(context.Background(), w, tc.req, mockComponent("fragment"), mockComponent("full"), tc.boostedAsFull)
+		if err != nil {
+			t.Errorf("%s: an error occurred rendering: %v", tc.name, err)
+		}
+
+		if string(w.body) != tc.body {
+			t.Errorf("%s: wrong response body. got=%q, want=%q", tc.name, string(w.body), tc.body)
+		}
+		if got := w.header.Get(HeaderRetarget); got != "#main" {
+			t.Errorf("%s: wrong value for header %q. got=%q, want=%q", tc.name, HeaderRetarget, got, "#main")
+		}
+	}
+}
+
+func TestRenderTemplCounted(t *testing.T) {
+	w := newMockResponseWriter()
+
+	n, err := NewResponse().RenderTemplCounted(context.Background(), w, mockComponent("hello"))
+	if err != nil {
+		t.Errorf("an error occurred rendering: %v", err)
+	}
+	if n != len("hello") {
+		t.Errorf("wrong byte count. got=%v, want=%v", n, len("hello"))
+	}
+
+	// Header errors return before rendering
+	w = newMockResponseWriter()
+	n, err = NewResponse().
+		AddTrigger(TriggerObject("bad", make(chan int))).
+		RenderTemplCounted(context.Background(), w, mockComponent("hello"))
+	if err == nil {
+		t.Errorf("expected an error writing an unserializable trigger")
+	}
+	if n != 0 || len(w.body) != 0 {
+		t.Errorf("expected nothing to be rendered. n=%v, body=%q", n, w.body)
+	}
+}
+
+func TestRenderTemplTo(t *testing.T) {
+	w := newMockResponseWriter()
+	base := NewResponse()
+
+	err := base.RenderTemplTo(context.Background(), w, "#sidebar", mockComponent("hello"))
+	if err != nil {
+		t.Errorf("an error occurred rendering: %v", err)
+	}
+
+	if _, ok := base.RawHeaders()[HeaderRetarget]; ok {
+		t.Errorf("header %q should not be set on the receiver", HeaderRetarget)
+	}
+
+	if got := w.header.Get(HeaderRetarget); got != "#sidebar" {
+		t.Errorf("wrong value for header %q. got=%q, want=%q", HeaderRetarget, got, "#sidebar")
+	}
+
+	if string(w.body) != "hello" {
+		t.Errorf("wrong response body. got=%q, want=%q", string(w.body), "hello")
+	}
+}
+
+func TestRenderTemplReq(t *testing.T) {
+	type ctxKey struct{}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req = req.WithContext(context.WithValue(req.Context(), ctxKey{}, "Joe"))
+
+	var component contextComponent = func(ctx context.Context, w io.Writer) error {
+		name, _ := ctx.Value(ctxKey{}).(string)
+		_, err := io.WriteString(w, "hello "+name)
+		return err
+	}
+
+	w := newMockResponseWriter()
+	err := NewResponse().Retarget("#hello").RenderTemplReq(req, w, component)
+	if err != nil {
+		t.Errorf("an error occurred rendering: %v", err)
+	}
+
+	if got := w.header.Get(HeaderRetarget); got != "#hello" {
+		t.Errorf("wrong value for header %q. got=%q, want=%q", HeaderRetarget, got, "#hello")
+	}
+
+	if string(w.body) != "hello Joe" {
+		t.Errorf("wrong response body. got=%q, want=%q", string(w.body), "hello Joe")
+	}
+}
+
+// contextComponent renders with a function that has access to the render context
+type contextComponent func(ctx context.Context, w io.Writer) error
+
+func (c contextComponent) Render(ctx context.Context, w io.Writer) error {
+	return c(ctx, w)
+}
+
+func TestRenderTemplNotifyOnError(t *testing.T) {
+	t.Run("render succeeds", func(t *testing.T) {
+		w := newMockResponseWriter()
+
+		err := NewResponse().RenderTemplNotifyOnError(context.Background(), w, mockComponent("hello"), "renderError")
+		if err != nil {
+			t.Errorf("an error occurred rendering: %v", err)
+		}
+
+		if got := w.header.Get(HeaderTrigger); got != "" {
+			t.Errorf("wrong value for header %q. got=%q, want=%q", HeaderTrigger, got, "")
+		}
+
+		if string(w.body) != "hello" {
+			t.Errorf("wrong response body. got=%q, want=%q", string(w.body), "hello")
+		}
+	})
+
+	t.Run("render fails", func(t *testing.T) {
+		w := newMockResponseWriter()
+		base := NewResponse()
+
+		err := base.RenderTemplNotifyOnError(context.Background(), w, failingComponent{}, "renderError")
+		if err == nil {
+			t.Errorf("expected an error from a failing component")
+		}
+
+		if headers := base.RawHeaders(); len(headers) != 0 {
+			t.Errorf("headers should not be set on the receiver, got=%v", headers)
+		}
+
+		if w.statusCode != http.StatusInternalServerError {
+			t.Errorf("wrong status code. want=%v, got=%v", http.StatusInternalServerError, w.statusCode)
+		}
+
+		expectedHeaders := map[string]string{
+			HeaderTrigger: "renderError",
+			HeaderReswap:  "none",
+		}
+
+		for k, v := range expectedHeaders {
+			if got := w.header.Get(k); got != v {
+				t.Errorf("wrong value for header %q. got=%q, want=%q", k, got, v)
+			}
+		}
+
+		if len(w.body) != 0 {
+			t.Errorf("expected no response body, got=%q", string(w.body))
+		}
+	})
+}
+
+func TestOnWritten(t *testing.T) {
+	var calls []string
+	record := func(name string) func() {
+		return func() { calls = append(calls, name) }
+	}
+
+	w := newMockResponseWriter()
+
+	err := NewResponse().
+		OnWritten(record("first")).
+		OnWritten(record("second")).
+		Write(w)
+	if err != nil {
+		t.Errorf("an error occurred writing a response: %v", err)
+	}
+
+	_, err = NewResponse().
+		OnWritten(record("render")).
+		RenderHTML(w, template.HTML("hello"))
+	if err != nil {
+		t.Errorf("an error occurred writing HTML: %v", err)
+	}
+
+	err = NewResponse().
+		AddTrigger(TriggerObject("bad", make(chan int))).
+		OnWritten(record("failed")).
+		Write(w)
+	if err == nil {
+		t.Errorf("expected an error writing an unserializable trigger")
+	}
+
+	err = NewResponse().
+		OnWritten(record("failed render")).
+		RenderTempl(context.Background(), w, failingComponent{})
+	if err == nil {
+		t.Errorf("expected an error from a failing component")
+	}
+
+	want := []string{"first", "second", "render"}
+	if len(calls) != len(want) {
+		t.Fatalf("wrong callbacks called. got=%v, want=%v", calls, want)
+	}
+	for i := range want {
+		if calls[i] != want[i] {
+			t.Errorf("wrong callbacks called. got=%v, want=%v", calls, want)
+		}
+	}
+}
+
+func TestPollResult(t *testing.T) {
+	render := func() (template.HTML, error) {
+		return template.HTML("<p>new</p>"), nil
+	}
+
+	t.Run("unchanged", func(t *testing.T) {
+		w := newMockResponseWriter()
+		base := NewResponse()
+
+		_, err := base.PollResult(w, false, func() (template.HTML, error) {
+			t.Errorf("render should not be called for unchanged results")
+			return render()
+		})
+		if err != nil {
+			t.Errorf("an error occurred writing a response: %v", err)
+		}
+
+		if _, ok := base.RawHeaders()[HeaderReswap]; ok {
+			t.Errorf("header %q should not be set on the receiver", HeaderReswap)
+		}
+
+		if w.statusCode != http.StatusNoContent {
+			t.Errorf("wrong status code. want=%v, got=%v", http.StatusNoContent, w.statusCode)
+		}
+		if got := w.header.Get(HeaderReswap); got != "none" {
+			t.Errorf("wrong value for header %q. got=%q, want=%q", HeaderReswap, got, "none")
+		}
+		if len(w.body) != 0 {
+			t.Errorf("expected no response body, got=%q", string(w.body))
+		}
+	})
+
+	t.Run("unchanged with status code", func(t *testing.T) {
+		w := newMockResponseWriter()
+
+		_, err := NewResponse().StopPollingIf(true).PollResult(w, false, render)
+		if err != nil {
+			t.Errorf("an error occurred writing a response: %v", err)
+		}
+
+		if w.statusCode != StatusStopPolling {
+			t.Errorf("wrong status code. want=%v, got=%v", StatusStopPolling, w.statusCode)
+		}
+		if got := w.header.Get(HeaderReswap); got != "none" {
+			t.Errorf("wrong value for header %q. got=%q, want=%q", HeaderReswap, got, "none")
+		}
+	})
+
+	t.Run("changed", func(t *testing.T) {
+		w := newMockResponseWriter()
+
+		_, err := NewResponse().PollResult(w, true, render)
+		if err != nil {
+			t.Errorf("an error occurred writing a response: %v", err)
+		}
+
+		if w.statusCode != 0 {
+			t.Errorf("status code should not be written. got=%v", w.statusCode)
+		}
+		if got := w.header.Get(HeaderReswap); got != "" {
+			t.Errorf("wrong value for header %q. got=%q, want=%q", HeaderReswap, got, "")
+		}
+		if string(w.body) != "<p>new</p>" {
+			t.Errorf("wrong response body. got=%q, want=%q", string(w.body), "<p>new</p>")
+		}
+	})
+}
+
+func TestErr(t *testing.T) {
+	if err := NewResponse().LocationWithContext("/hello", LocationContext{Target: "#main"}).Err(); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+
+	r := NewResponse().LocationWithContext("", LocationContext{Target: "#main"})
+
+	if err := r.Err(); err == nil {
+		t.Errorf("expected an error")
+	}
+
+	if err := r.Write(newMockResponseWriter()); err == nil {
+		t.Errorf("expected Write to return the accumulated error")
+	}
+}
+
+func TestWriteAndFlush(t *testing.T) {
+	w := &mockFlusher{mockResponseWriter: newMockResponseWriter()}
+
+	err := NewResponse().Retarget("#stream").WriteAndFlush(w)
+	if err != nil {
+		t.Errorf("an error occurred writing a response: %v", err)
+	}
+
+	if w.flushes != 1 {
+		t.Errorf("wrong number of flushes. got=%v, want=%v", w.flushes, 1)
+	}
+	if w.statusCode != http.StatusOK {
+		t.Errorf("wrong status code. want=%v, got=%v", http.StatusOK, w.statusCode)
+	}
+	if got := w.header.Get(HeaderRetarget); got != "#stream" {
+		t.Errorf("wrong value for header %q. got=%q, want=%q", HeaderRetarget, got, "#stream")
+	}
+
+	// Falls back to Write for writers that can't flush
+	plain := newMockResponseWriter()
+	if err := NewResponse().Retarget("#stream").WriteAndFlush(plain); err != nil {
+		t.Errorf("an error occurred writing a response: %v", err)
+	}
+	if plain.statusCode != 0 {
+		t.Errorf("status code should not be written. got=%v", plain.statusCode)
+	}
+}
+
+type countingHeaderWriter struct {
+	*mockResponseWriter
+	writeHeaderCalls int
+}
+
+func (w *countingHeaderWriter) WriteHeader(statusCode int) {
+	w.writeHeaderCalls++
+	w.mockResponseWriter.WriteHeader(statusCode)
+}
+
+type mockFlusher struct {
+	*mockResponseWriter
+	flushes int
+}
+
+func (f *mockFlusher) Flush() {
+	f.flushes++
+}
+
+type mockComponent string
+
+func (c mockComponent) Render(ctx context.Context, w io.Writer) error {
+	_, err := io.WriteString(w, string(c))
+	return err
+}
+
+type failingComponent struct{}
+
+func (c failingComponent) Render(ctx context.Context, w io.Writer) error {
+	_, _ = io.WriteString(w, "partial")
+	return errors.New("render failed")
+}
+
 type mockResponseWriter struct {
 	body       []byte
 	statusCode int
@@ -94,9 +729,258 @@ func (mrw *mockResponseWriter) Header() http.Header {
 
 func (mrw *mockResponseWriter) Write(b []byte) (int, error) {
 	mrw.body = append(mrw.body, b...)
-	return 0, nil
+	return len(b), nil
 }
 
 func (mrw *mockResponseWriter) WriteHeader(statusCode int) {
 	mrw.statusCode = statusCode
 }
+
+func TestValidate(t *testing.T) {
+	testCases := []struct {
+		name     string
+		response Response
+		wantErr  bool
+	}{
+		{
+			name:     "no headers",
+			response: NewResponse(),
+			wantErr:  false,
+		},
+		{
+			name:     "reselect with inner html swap",
+			response: NewResponse().Reselect("#hello").Reswap(SwapInnerHTML),
+			wantErr:  false,
+		},
+		{
+			name:     "reselect with none swap",
+			response: NewResponse().Reselect("#hello").Reswap(SwapNone),
+			wantErr:  true,
+		},
+		{
+			name:     "redirect and location",
+			response: NewResponse().Redirect("/a").Location("/b"),
+			wantErr:  true,
+		},
+		{
+			name:     "refresh and redirect",
+			response: NewResponse().Refresh(true).Redirect("/a"),
+			wantErr:  true,
+		},
+		{
+			name:     "no refresh and redirect",
+			response: NewResponse().Refresh(false).Redirect("/a"),
+			wantErr:  false,
+		},
+		{
+			name:     "reswap with scroll and show",
+			response: NewResponse().Reswap(SwapInnerHTML.Show(Top).Scroll(Bottom)),
+			wantErr:  true,
+		},
+		{
+			name:     "reselect with delete swap and modifier",
+			response: NewResponse().Reselect("#hello").Reswap(SwapDelete.After(time.Second)),
+			wantErr:  true,
+		},
+	}
+
+	for _, tc := range testCases {
+		if err := tc.response.Validate(); (err != nil) != tc.wantErr {
+			t.Errorf("%s: got error %v, want error: %v", tc.name, err, tc.wantErr)
+		}
+	}
+}
+
+func TestString(t *testing.T) {
+	testCases := []struct {
+		name string
+		res  Response
+		want string
+	}{
+		{
+			name: "empty",
+			res:  NewResponse(),
+			want: "htmx.Response\n" +
+				"  Status: (unset)\n",
+		},
+		{
+			name: "headers and triggers",
+			res: NewResponse().
+				StatusCode(http.StatusCreated).
+				Retarget("#contacts").
+				PushURL("/contacts").
+				AddTrigger(Trigger("contactAdded")).
+				AddTriggerAfterSwap(TriggerDetail("showMessage", "Saved")),
+			want: "htmx.Response\n" +
+				"  Status: 201\n" +
+				"  HX-Push-Url: /contacts\n" +
+				"  HX-Retarget: #contacts\n" +
+				"  HX-Trigger: contactAdded\n" +
+				`  HX-Trigger-After-Swap: {"showMessage":"Saved"}` + "\n",
+		},
+		{
+			name: "trigger marshal error",
+			res: NewResponse().
+				Redirect("/login").
+				AddTrigger(TriggerObject("bad", make(chan int))),
+			want: "htmx.Response\n" +
+				"  Status: (unset)\n" +
+				"  HX-Redirect: /login\n" +
+				"  HX-Trigger: <trigger marshal error>\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		if got := tc.res.String(); got != tc.want {
+			t.Errorf("%s: got:\n%s\nwant:\n%s", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestOnError(t *testing.T) {
+	var errs []error
+	capture := func(err error) { errs = append(errs, err) }
+
+	res := NewResponse().
+		AddTrigger(TriggerObject("bad", make(chan int))).
+		OnError(capture)
+
+	testCases := []struct {
+		name  string
+		write func(w http.ResponseWriter) error
+	}{
+		{
+			name:  "Write",
+			write: res.Write,
+		},
+		{
+			name: "RenderHTML",
+			write: func(w http.ResponseWriter) error {
+				_, err := res.RenderHTML(w, "<p>hi</p>")
+				return err
+			},
+		},
+		{
+			name: "RenderHTMLOOB",
+			write: func(w http.ResponseWriter) error {
+				_, err := res.RenderHTMLOOB(w, "<p>hi</p>", "<p>oob</p>")
+				return err
+			},
+		},
+		{
+			name: "RenderTempl",
+			write: func(w http.ResponseWriter) error {
+				return res.RenderTempl(context.Background(), w, mockComponent("<p>hi</p>"))
+			},
+		},
+		{
+			name: "RenderTemplTo",
+			write: func(w http.ResponseWriter) error {
+				return res.RenderTemplTo(context.Background(), w, "#main", mockComponent("<p>hi</p>"))
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		errs = nil
+
+		err := tc.write(newMockResponseWriter())
+		if err == nil {
+			t.Errorf("%s: expected an error", tc.name)
+			continue
+		}
+		if len(errs) != 1 {
+			t.Errorf("%s: wrong number of OnError calls. got=%d, want=%d", tc.name, len(errs), 1)
+			continue
+		}
+		if errs[0] != err {
+			t.Errorf(`%s: got: "%v", want: "%v"`, tc.name, errs[0], err)
+		}
+	}
+
+	errs = nil
+	err := NewResponse().OnError(capture).RenderTempl(context.Background(), newMockResponseWriter(), failingComponent{})
+	if err == nil || len(errs) != 1 {
+		t.Errorf("failed render should call OnError once. got=%d calls", len(errs))
+	}
+
+	errs = nil
+	if err := NewResponse().OnError(capture).Write(newMockResponseWriter()); err != nil {
+		t.Errorf("an error occurred writing a response: %v", err)
+	}
+	if len(errs) != 0 {
+		t.Errorf("OnError should not be called for a successful write. got=%d calls", len(errs))
+	}
+}
+
+func TestConstructors(t *testing.T) {
+	testCases := []struct {
+		name       string
+		res        Response
+		want       map[string]string
+		statusCode int // 0 if no status code is written
+	}{
+		{
+			name: "Redirect",
+			res:  Redirect("/login"),
+			want: map[string]string{HeaderRedirect: "/login"},
+		},
+		{
+			name: "Refresh",
+			res:  Refresh(),
+			want: map[string]string{HeaderRefresh: "true"},
+		},
+		{
+			name:       "StopPolling",
+			res:        StopPolling(),
+			want:       map[string]string{},
+			statusCode: StatusStopPolling,
+		},
+	}
+
+	for _, tc := range testCases {
+		w := newMockResponseWriter()
+		tc.res.MustWrite(w)
+
+		for k, v := range tc.want {
+			if got := w.Header().Get(k); got != v {
+				t.Errorf("%s: wrong value for header %q. got=%q, want=%q", tc.name, k, got, v)
+			}
+		}
+		if w.statusCode != tc.statusCode {
+			t.Errorf("%s: wrong status code. got=%d, want=%d", tc.name, w.statusCode, tc.statusCode)
+		}
+	}
+}
+
+func TestAccumulators_Branches(t *testing.T) {
+	var calls []string
+	record := func(name string) func() {
+		return func() { calls = append(calls, name) }
+	}
+
+	base := NewResponse().
+		OnWritten(record("a")).OnWritten(record("b")).OnWritten(record("c")).
+		OnError(func(error) {}).OnError(func(error) {}).OnError(func(error) {}).
+		Cookie(&http.Cookie{Name: "a"}).Cookie(&http.Cookie{Name: "b"}).Cookie(&http.Cookie{Name: "c"})
+
+	x := base.OnWritten(record("x")).Cookie(&http.Cookie{Name: "x"}).OnError(func(error) {})
+	base.OnWritten(record("y")).Cookie(&http.Cookie{Name: "y"}).OnError(func(error) {})
+
+	w := newMockResponseWriter()
+	if err := x.Write(w); err != nil {
+		t.Errorf("an error occurred writing a response: %v", err)
+	}
+
+	if got := strings.Join(calls, ","); got != "a,b,c,x" {
+		t.Errorf(`wrong OnWritten calls. got: "%v", want: "%v"`, got, "a,b,c,x")
+	}
+
+	cookies := w.Header().Values("Set-Cookie")
+	if len(cookies) != 4 || cookies[3] != "x=" {
+		t.Errorf("wrong cookies. got=%q", cookies)
+	}
+	if len(x.onError) != 4 {
+		t.Errorf("wrong number of OnError callbacks. got=%d, want=%d", len(x.onError), 4)
+	}
+}
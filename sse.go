@@ -0,0 +1,46 @@
+package htmx
+
+import (
+	"errors"
+	"html/template"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// WriteSSE writes an HTML fragment as a server-sent event frame for the HTMX
+// 'sse' extension, flushing it if the response writer implements [http.Flusher].
+//
+// Each line of the HTML is written as its own 'data:' line, as required by the
+// server-sent events format. The event name must not contain line breaks.
+//
+// The response should be set up for server-sent events beforehand, e.g. with the
+// 'Content-Type: text/event-stream' header.
+//
+// For more info, see https://htmx.org/extensions/sse/
+func WriteSSE(w http.ResponseWriter, event string, data template.HTML) error {
+	if strings.ContainsAny(event, "\r\n") {
+		return errors.New("server-sent event name must not contain line breaks")
+	}
+
+	var b strings.Builder
+	if event != "" {
+		b.WriteString("event: " + event + "\n")
+	}
+
+	lines := strings.NewReplacer("\r\n", "\n", "\r", "\n").Replace(string(data))
+	for _, line := range strings.Split(lines, "\n") {
+		b.WriteString("data: " + line + "\n")
+	}
+	b.WriteString("\n")
+
+	if _, err := io.WriteString(w, b.String()); err != nil {
+		return err
+	}
+
+	if flusher, ok := w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+
+	return nil
+}
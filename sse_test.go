@@ -0,0 +1,31 @@
+package htmx
+
+import (
+	"testing"
+)
+
+func TestWriteSSE(t *testing.T) {
+	w := &mockFlusher{mockResponseWriter: newMockResponseWriter()}
+
+	err := WriteSSE(w, "message", "<div>\n  <p>Hello</p>\r\n</div>")
+	if err != nil {
+		t.Errorf("an error occurred writing an event: %v", err)
+	}
+
+	want := "event: message\n" +
+		"data: <div>\n" +
+		"data:   <p>Hello</p>\n" +
+		"data: </div>\n" +
+		"\n"
+	if string(w.body) != want {
+		t.Errorf("wrong frame. got=%q, want=%q", string(w.body), want)
+	}
+
+	if w.flushes != 1 {
+		t.Errorf("wrong number of flushes. got=%v, want=%v", w.flushes, 1)
+	}
+
+	if err := WriteSSE(newMockResponseWriter(), "bad\nevent", ""); err == nil {
+		t.Errorf("expected an error for an event name with a line break")
+	}
+}
@@ -0,0 +1,125 @@
+package htmx
+
+import (
+	"bytes"
+	"errors"
+	"html/template"
+	"net/http"
+)
+
+// Streamer writes HTML fragments of a streamed response as they become ready.
+//
+// Use [htmx.Response.StreamHTML] to create one.
+type Streamer struct {
+	r       Response
+	w       http.ResponseWriter
+	flusher http.Flusher
+
+	// Fragments buffered until Close, if the response writer can't flush
+	buf *bytes.Buffer
+
+	// The first error from writing a fragment, returned by later writes and Close
+	err error
+
+	closed bool
+}
+
+// errStreamClosed is returned when writing to a closed Streamer.
+var errStreamClosed = errors.New("write to closed htmx.Streamer")
+
+// StreamHTML writes the defined HTMX headers and returns a [htmx.Streamer]
+// for writing HTML fragments progressively, e.g. for long-running handlers.
+//
+// If no status code is set, 200 OK is written, and the headers are sent to the client
+// immediately. Each fragment written is flushed right away.
+//
+// If the response writer does not implement [http.Flusher], fragments can't be sent
+// progressively, so they are buffered and written all at once by [htmx.Streamer.Close].
+//
+// The [htmx.Response.OnWritten] callbacks run when the streamer is closed.
+func (r Response) StreamHTML(w http.ResponseWriter) (*Streamer, error) {
+	err := r.write(w)
+	if err != nil {
+		return nil, r.failed(err)
+	}
+
+	s := &Streamer{r: r, w: w}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		s.buf = &bytes.Buffer{}
+		return s, nil
+	}
+
+	if r.statusCode == 0 {
+		w.WriteHeader(http.StatusOK)
+	}
+	flusher.Flush()
+
+	s.flusher = flusher
+	return s, nil
+}
+
+// Write writes an HTML fragment to the stream and flushes it to the client.
+//
+// If the response writer can't flush, the fragment is buffered until [htmx.Streamer.Close].
+//
+// Once a write fails, the stream is broken: later writes return the same error
+// without writing the fragment.
+func (s *Streamer) Write(html template.HTML) error {
+	if s.err != nil {
+		return s.err
+	}
+	if s.closed {
+		return s.r.failed(errStreamClosed)
+	}
+
+	if s.buf != nil {
+		s.buf.WriteString(string(html))
+		return nil
+	}
+
+	_, err := s.w.Write([]byte(html))
+	if err != nil {
+		s.err = s.r.failed(err)
+		return s.err
+	}
+
+	s.flusher.Flush()
+	return nil
+}
+
+// Close finishes the stream, writing any buffered fragments followed by the
+// out-of-band fragments added with [htmx.Response.AddOOB].
+//
+// If a write failed, Close returns its error without writing anything, and the
+// [htmx.Response.OnWritten] callbacks don't run.
+// Closing a streamer more than once does nothing.
+func (s *Streamer) Close() error {
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+
+	if s.err != nil {
+		return s.err
+	}
+
+	if s.buf != nil {
+		_, err := s.buf.WriteTo(s.w)
+		if err != nil {
+			return s.r.failed(err)
+		}
+	}
+
+	err := s.r.writeOOB(s.w)
+	if err != nil {
+		return s.r.failed(err)
+	}
+	if s.flusher != nil {
+		s.flusher.Flush()
+	}
+
+	s.r.written()
+	return nil
+}
@@ -0,0 +1,135 @@
+package htmx
+
+import (
+	"errors"
+	"html/template"
+	"net/http"
+	"testing"
+)
+
+func TestStreamHTML(t *testing.T) {
+	w := &mockFlusher{mockResponseWriter: newMockResponseWriter()}
+
+	written := false
+	s, err := NewResponse().
+		Retarget("#results").
+		OnWritten(func() { written = true }).
+		StreamHTML(w)
+	if err != nil {
+		t.Fatalf("an error occurred streaming a response: %v", err)
+	}
+
+	if got := w.Header().Get(HeaderRetarget); got != "#results" {
+		t.Errorf("wrong value for header %q. got=%q, want=%q", HeaderRetarget, got, "#results")
+	}
+	if w.statusCode != http.StatusOK {
+		t.Errorf("wrong status code. want=%v, got=%v", http.StatusOK, w.statusCode)
+	}
+	if w.flushes != 1 {
+		t.Errorf("wrong number of flushes after headers. got=%v, want=%v", w.flushes, 1)
+	}
+
+	for _, fragment := range []string{"<li>1</li>", "<li>2</li>"} {
+		if err := s.Write(template.HTML(fragment)); err != nil {
+			t.Errorf("an error occurred writing a fragment: %v", err)
+		}
+	}
+
+	if w.flushes != 3 {
+		t.Errorf("wrong number of flushes after fragments. got=%v, want=%v", w.flushes, 3)
+	}
+	if got := string(w.body); got != "<li>1</li><li>2</li>" {
+		t.Errorf(`got: "%v", want: "%v"`, got, "<li>1</li><li>2</li>")
+	}
+	if written {
+		t.Errorf("OnWritten callbacks should not run before the stream is closed")
+	}
+
+	if err := s.Close(); err != nil {
+		t.Errorf("an error occurred closing a stream: %v", err)
+	}
+	if !written {
+		t.Errorf("OnWritten callbacks should run when the stream is closed")
+	}
+	if err := s.Write("<li>3</li>"); err == nil {
+		t.Errorf("expected an error writing to a closed stream")
+	}
+}
+
+func TestStreamHTML_NoFlusher(t *testing.T) {
+	w := newMockResponseWriter()
+
+	s, err := NewResponse().StreamHTML(w)
+	if err != nil {
+		t.Fatalf("an error occurred streaming a response: %v", err)
+	}
+
+	if err := s.Write("<li>1</li>"); err != nil {
+		t.Errorf("an error occurred writing a fragment: %v", err)
+	}
+	if len(w.body) != 0 {
+		t.Errorf("fragments should be buffered until the stream is closed, got=%q", w.body)
+	}
+
+	if err := s.Close(); err != nil {
+		t.Errorf("an error occurred closing a stream: %v", err)
+	}
+	if got := string(w.body); got != "<li>1</li>" {
+		t.Errorf(`got: "%v", want: "%v"`, got, "<li>1</li>")
+	}
+}
+
+func TestStreamHTML_Error(t *testing.T) {
+	w := &mockFlusher{mockResponseWriter: newMockResponseWriter()}
+
+	_, err := NewResponse().
+		AddTrigger(TriggerObject("bad", make(chan int))).
+		StreamHTML(w)
+	if err == nil {
+		t.Errorf("expected an error")
+	}
+	if w.flushes != 0 {
+		t.Errorf("a failed stream should not flush. got=%v flushes", w.flushes)
+	}
+}
+
+type failingFlusher struct {
+	*mockFlusher
+}
+
+func (f *failingFlusher) Write(b []byte) (int, error) {
+	return 0, errors.New("connection closed")
+}
+
+func TestStreamHTML_WriteError(t *testing.T) {
+	w := &failingFlusher{mockFlusher: &mockFlusher{mockResponseWriter: newMockResponseWriter()}}
+
+	var written bool
+	var errs int
+	s, err := NewResponse().
+		AddOOB("count", SwapInnerHTML, "2").
+		OnWritten(func() { written = true }).
+		OnError(func(error) { errs++ }).
+		StreamHTML(w)
+	if err != nil {
+		t.Fatalf("an error occurred streaming a response: %v", err)
+	}
+
+	writeErr := s.Write("<li>1</li>")
+	if writeErr == nil {
+		t.Fatalf("expected an error writing a fragment")
+	}
+	if err := s.Write("<li>2</li>"); err != writeErr {
+		t.Errorf("wrong error writing to a broken stream. got=%v, want=%v", err, writeErr)
+	}
+	if err := s.Close(); err != writeErr {
+		t.Errorf("wrong error closing a broken stream. got=%v, want=%v", err, writeErr)
+	}
+
+	if written {
+		t.Errorf("OnWritten callbacks should not run after a failed write")
+	}
+	if errs != 1 {
+		t.Errorf("wrong number of OnError calls. got=%v, want=%v", errs, 1)
+	}
+}
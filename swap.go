@@ -1,6 +1,9 @@
 package htmx
 
 import (
+	"errors"
+	"fmt"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -9,6 +12,8 @@ import (
 // of [htmx.Response.Reswap] and [LocationContext].
 //
 // SwapStrategy methods add modifiers to change the behavior of the swap.
+// With [SwapNone] and [SwapDelete], no content is swapped in, so modifiers
+// are effectively no-ops; see [SwapStrategy.IsSwappable].
 type SwapStrategy string
 
 const (
@@ -66,16 +71,28 @@ func (s SwapStrategy) swapString() string {
 	return string(s)
 }
 
-// join joins any amount of strings together with a space in between.
+// String returns the 'hx-swap' value of the strategy, including its modifiers.
+//
+// String implements [fmt.Stringer].
+func (s SwapStrategy) String() string {
+	return s.swapString()
+}
+
+// join joins any amount of strings together with a space in between,
+// skipping empty strings so there are never repeated spaces.
 func join(elems ...string) string {
-	// TrimSpace is needed because strings.Join inserts
-	// the separator string (spaces) at the start of the string
-	return strings.TrimSpace(strings.Join(elems, " "))
+	nonEmpty := make([]string, 0, len(elems))
+	for _, elem := range elems {
+		if elem = strings.TrimSpace(elem); elem != "" {
+			nonEmpty = append(nonEmpty, elem)
+		}
+	}
+	return strings.Join(nonEmpty, " ")
 }
 
 func (s SwapStrategy) cutPrefix(prefix string) string {
-	words := strings.Split(s.swapString(), " ")
-	filteredWords := make([]string, len(words))
+	words := strings.Fields(s.swapString())
+	filteredWords := make([]string, 0, len(words))
 
 	for _, word := range words {
 		if strings.HasPrefix(word, prefix+":") {
@@ -106,6 +123,12 @@ func (s SwapStrategy) timeModifier(prefix string, duration time.Duration) SwapSt
 	return SwapStrategy(v)
 }
 
+func (s SwapStrategy) millisModifier(prefix string, ms int) SwapStrategy {
+	v := s.cutPrefix(prefix)
+	v = join(v, prefix+":"+strconv.Itoa(ms)+"ms")
+	return SwapStrategy(v)
+}
+
 // Transition makes the swap use the new View Transitions API.
 //
 // Adds the 'transition:<true | false>' modifier.
@@ -157,6 +180,30 @@ func (s SwapStrategy) SettleAfter(duration time.Duration) SwapStrategy {
 	return s.timeModifier("settle", duration)
 }
 
+// AfterMillis modifies the amount of time in milliseconds that HTMX will wait
+// after receiving a response to swap the content.
+//
+// Unlike [SwapStrategy.After], the value is always written in milliseconds.
+//
+// Adds the 'swap:<ms>ms' modifier.
+//
+// For more info, see https://htmx.org/attributes/hx-swap/
+func (s SwapStrategy) AfterMillis(ms int) SwapStrategy {
+	return s.millisModifier("swap", ms)
+}
+
+// SettleMillis modifies the amount of time in milliseconds that HTMX will wait
+// after the swap before executing the settle logic.
+//
+// Unlike [SwapStrategy.SettleAfter], the value is always written in milliseconds.
+//
+// Adds the 'settle:<ms>ms' modifier.
+//
+// For more info, see https://htmx.org/attributes/hx-swap/
+func (s SwapStrategy) SettleMillis(ms int) SwapStrategy {
+	return s.millisModifier("settle", ms)
+}
+
 type (
 	// Direction is a value for the [htmx.SwapStrategy] 'scroll' and 'show' modifier methods.
 	//
@@ -188,6 +235,8 @@ const (
 //
 // Adds the 'scroll:<direction ("top" | "bottom")>' modifier.
 //
+// Conflicts with the 'show' modifier, see [SwapStrategy.Validate].
+//
 // For more info, see https://htmx.org/attributes/hx-swap/
 func (s SwapStrategy) Scroll(direction Direction) SwapStrategy {
 	v := s.cutPrefix("scroll")
@@ -225,6 +274,17 @@ func (s SwapStrategy) ScrollWindow(direction Direction) SwapStrategy {
 	return SwapStrategy(join(v, mod))
 }
 
+// ScrollNone disables 'scroll'.
+//
+// Adds the 'scroll:none' modifier.
+//
+// For more info, see https://htmx.org/attributes/hx-swap/
+func (s SwapStrategy) ScrollNone() SwapStrategy {
+	v := s.cutPrefix("scroll")
+	mod := "scroll:none"
+	return SwapStrategy(join(v, mod))
+}
+
 // Show changes the show behavior based on the given direction.
 //
 // Show([htmx.Top]) shows the top of the swapped-in element.
@@ -233,6 +293,8 @@ func (s SwapStrategy) ScrollWindow(direction Direction) SwapStrategy {
 //
 // Adds the 'show:<direction ("top" | "bottom")>' modifier.
 //
+// Conflicts with the 'scroll' modifier, see [SwapStrategy.Validate].
+//
 // For more info, see https://htmx.org/attributes/hx-swap/
 func (s SwapStrategy) Show(direction Direction) SwapStrategy {
 	v := s.cutPrefix("show")
@@ -280,3 +342,111 @@ func (s SwapStrategy) ShowNone() SwapStrategy {
 	mod := "show:none"
 	return SwapStrategy(join(v, mod))
 }
+
+// style returns the swap style of the strategy without its modifiers,
+// or [SwapDefault] if no swap style is set.
+func (s SwapStrategy) style() SwapStrategy {
+	words := strings.Fields(s.swapString())
+	if len(words) == 0 || strings.Contains(words[0], ":") {
+		return SwapDefault
+	}
+	return SwapStrategy(words[0])
+}
+
+// IsSwappable returns true if the swap style of the strategy swaps in content from the response.
+//
+// Returns false for [SwapNone] and [SwapDelete], with or without modifiers.
+func (s SwapStrategy) IsSwappable() bool {
+	switch s.style() {
+	case SwapNone, SwapDelete:
+		return false
+	}
+	return true
+}
+
+// hasModifier returns true if the strategy has a modifier with the given prefix.
+func (s SwapStrategy) hasModifier(prefix string) bool {
+	_, ok := s.modifierValue(prefix)
+	return ok
+}
+
+// modifierValue returns the value of the modifier with the given prefix,
+// and false if the strategy doesn't have that modifier.
+func (s SwapStrategy) modifierValue(prefix string) (string, bool) {
+	for _, word := range strings.Fields(s.swapString()) {
+		if value, ok := strings.CutPrefix(word, prefix+":"); ok {
+			return value, true
+		}
+	}
+	return "", false
+}
+
+// Validate reports modifiers of the strategy that conflict with each other.
+//
+// Validate detects these conflicts:
+//
+//   - Both 'scroll' and 'show' modifiers are set. Both are valid syntax, but they give
+//     HTMX conflicting instructions at runtime, so in practice they are mutually exclusive.
+//     Modifiers disabled with 'none', e.g. by [SwapStrategy.ScrollNone], don't conflict.
+func (s SwapStrategy) Validate() error {
+	scroll, hasScroll := s.modifierValue("scroll")
+	show, hasShow := s.modifierValue("show")
+	if hasScroll && hasShow && scroll != "none" && show != "none" {
+		return errors.New("swap strategy has both 'scroll' and 'show' modifiers")
+	}
+	return nil
+}
+
+// SwapConfig describes a swap strategy as data, so named swap behaviors can be
+// defined in one place and compiled with [SwapConfig.Build].
+//
+// Zero-valued fields don't add modifiers.
+type SwapConfig struct {
+	// The swap style, such as [SwapInnerHTML]. Must not have modifiers.
+	Style SwapStrategy
+	// Whether the swap uses the View Transitions API.
+	Transition bool
+	// The amount of time to wait after the swap before settling.
+	Settle time.Duration
+	// The direction to scroll the swapped-in element to.
+	Scroll Direction
+	// The direction to show the swapped-in element at.
+	Show Direction
+}
+
+// Build compiles the config to a [SwapStrategy] using its modifier methods.
+//
+// Returns an error if the style is unknown or has modifiers, if the settle
+// duration is negative, or if the resulting strategy fails [SwapStrategy.Validate].
+func (c SwapConfig) Build() (SwapStrategy, error) {
+	switch c.Style {
+	case SwapInnerHTML, SwapOuterHTML, SwapBeforeBegin, SwapAfterBegin,
+		SwapBeforeEnd, SwapAfterEnd, SwapDelete, SwapNone, SwapDefault:
+	default:
+		return "", fmt.Errorf("unknown swap style %q", c.Style)
+	}
+
+	if c.Settle < 0 {
+		return "", fmt.Errorf("negative settle duration %v", c.Settle)
+	}
+
+	s := c.Style
+	if c.Transition {
+		s = s.Transition(true)
+	}
+	if c.Settle > 0 {
+		s = s.SettleAfter(c.Settle)
+	}
+	if c.Scroll != nil {
+		s = s.Scroll(c.Scroll)
+	}
+	if c.Show != nil {
+		s = s.Show(c.Show)
+	}
+
+	if err := s.Validate(); err != nil {
+		return "", err
+	}
+
+	return s, nil
+}
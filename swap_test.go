@@ -1,6 +1,9 @@
 package htmx
 
 import (
+	"fmt"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 )
@@ -44,3 +47,253 @@ func TestSwapStrategy_SwapString(t *testing.T) {
 		}
 	}
 }
+
+func TestSwapStrategy_Validate(t *testing.T) {
+	testCases := []struct {
+		name         string
+		swapStrategy SwapStrategy
+		wantErr      bool
+	}{
+		{
+			name:         "no modifier",
+			swapStrategy: SwapInnerHTML,
+			wantErr:      false,
+		},
+		{
+			name:         "scroll only",
+			swapStrategy: SwapInnerHTML.Scroll(Bottom),
+			wantErr:      false,
+		},
+		{
+			name:         "show only",
+			swapStrategy: SwapInnerHTML.ShowWindow(Top),
+			wantErr:      false,
+		},
+		{
+			name:         "scroll and show",
+			swapStrategy: SwapInnerHTML.Show(Top).Scroll(Bottom),
+			wantErr:      true,
+		},
+		{
+			name:         "scroll none and show",
+			swapStrategy: SwapInnerHTML.ScrollNone().Show(Top),
+			wantErr:      false,
+		},
+		{
+			name:         "scroll and show none",
+			swapStrategy: SwapInnerHTML.Scroll(Bottom).ShowNone(),
+			wantErr:      false,
+		},
+	}
+
+	for _, tc := range testCases {
+		if err := tc.swapStrategy.Validate(); (err != nil) != tc.wantErr {
+			t.Errorf("%s: got error %v, want error: %v", tc.name, err, tc.wantErr)
+		}
+	}
+}
+
+func TestSwapStrategy_Millis(t *testing.T) {
+	testCases := []struct {
+		name         string
+		swapStrategy SwapStrategy
+		result       string
+	}{
+		{
+			name:         "zero",
+			swapStrategy: SwapInnerHTML.AfterMillis(0),
+			result:       "innerHTML swap:0ms",
+		},
+		{
+			name:         "sub-second",
+			swapStrategy: SwapInnerHTML.AfterMillis(250).SettleMillis(20),
+			result:       "innerHTML swap:250ms settle:20ms",
+		},
+		{
+			name:         "over a minute",
+			swapStrategy: SwapOuterHTML.SettleMillis(90000),
+			result:       "outerHTML settle:90000ms",
+		},
+		{
+			name:         "replaces duration modifier",
+			swapStrategy: SwapInnerHTML.After(time.Second).AfterMillis(1500),
+			result:       "innerHTML swap:1500ms",
+		},
+	}
+
+	for _, tc := range testCases {
+		if result := tc.swapStrategy.swapString(); result != tc.result {
+			t.Errorf(`%s: got: "%v", want: "%v"`, tc.name, result, tc.result)
+		}
+	}
+}
+
+func TestSwapStrategy_RepeatedModifier(t *testing.T) {
+	testCases := []struct {
+		name         string
+		swapStrategy SwapStrategy
+		result       string
+	}{
+		{
+			name:         "same modifier twice",
+			swapStrategy: SwapInnerHTML.Scroll(Top).Scroll(Bottom),
+			result:       "innerHTML scroll:bottom",
+		},
+		{
+			name:         "modifier in the middle replaced",
+			swapStrategy: SwapInnerHTML.Transition(true).Scroll(Top).IgnoreTitle(true).Transition(false),
+			result:       "innerHTML scroll:top ignoreTitle:true transition:false",
+		},
+		{
+			name:         "default swap",
+			swapStrategy: SwapDefault.ShowNone().ShowNone(),
+			result:       "show:none",
+		},
+	}
+
+	for _, tc := range testCases {
+		if result := tc.swapStrategy.swapString(); result != tc.result {
+			t.Errorf(`%s: got: "%v", want: "%v"`, tc.name, result, tc.result)
+		}
+	}
+}
+
+func TestSwapStrategy_ScrambledModifiers(t *testing.T) {
+	testCases := []struct {
+		name         string
+		swapStrategy SwapStrategy
+	}{
+		{
+			name: "after, scroll, settle",
+			swapStrategy: SwapInnerHTML.
+				After(time.Second).Scroll(Top).SettleAfter(time.Second).
+				ScrollNone().After(2 * time.Second),
+		},
+		{
+			name: "settle, after, scroll",
+			swapStrategy: SwapInnerHTML.
+				SettleAfter(time.Second).ScrollNone().After(time.Second).
+				Scroll(Bottom).SettleAfter(time.Second).ScrollNone().After(2 * time.Second),
+		},
+		{
+			name: "scroll, settle, after",
+			swapStrategy: SwapInnerHTML.
+				Scroll(Bottom).SettleAfter(time.Second).After(2 * time.Second).
+				ScrollNone().SettleAfter(time.Second),
+		},
+	}
+
+	for _, tc := range testCases {
+		words := strings.Fields(tc.swapStrategy.swapString())
+		if len(words) == 0 || words[0] != "innerHTML" {
+			t.Errorf("%s: swap style should come first, got %q", tc.name, tc.swapStrategy)
+		}
+
+		seen := map[string]bool{}
+		for _, word := range words[1:] {
+			key, _, _ := strings.Cut(word, ":")
+			if seen[key] {
+				t.Errorf("%s: duplicate %q modifier in %q", tc.name, key, tc.swapStrategy)
+			}
+			seen[key] = true
+		}
+
+		for _, mod := range []string{"swap:2s", "settle:1s", "scroll:none"} {
+			if !slices.Contains(words, mod) {
+				t.Errorf("%s: missing modifier %q in %q", tc.name, mod, tc.swapStrategy)
+			}
+		}
+	}
+}
+
+func TestSwapStrategy_IsSwappable(t *testing.T) {
+	testCases := []struct {
+		name         string
+		swapStrategy SwapStrategy
+		result       bool
+	}{
+		{name: "inner html", swapStrategy: SwapInnerHTML, result: true},
+		{name: "default with modifier", swapStrategy: SwapDefault.Transition(true), result: true},
+		{name: "none", swapStrategy: SwapNone, result: false},
+		{name: "none with modifier", swapStrategy: SwapNone.Transition(true), result: false},
+		{name: "delete", swapStrategy: SwapDelete, result: false},
+		{name: "delete with modifier", swapStrategy: SwapDelete.After(time.Second), result: false},
+	}
+
+	for _, tc := range testCases {
+		if result := tc.swapStrategy.IsSwappable(); result != tc.result {
+			t.Errorf(`%s: got: "%v", want: "%v"`, tc.name, result, tc.result)
+		}
+	}
+}
+
+func TestSwapConfig_Build(t *testing.T) {
+	testCases := []struct {
+		name    string
+		config  SwapConfig
+		result  string
+		wantErr bool
+	}{
+		{
+			name:   "style only",
+			config: SwapConfig{Style: SwapOuterHTML},
+			result: "outerHTML",
+		},
+		{
+			name: "all modifiers",
+			config: SwapConfig{
+				Style:      SwapBeforeEnd,
+				Transition: true,
+				Settle:     100 * time.Millisecond,
+				Scroll:     Bottom,
+			},
+			result: "beforeend transition:true settle:100ms scroll:bottom",
+		},
+		{
+			name:   "default style",
+			config: SwapConfig{Show: Top},
+			result: "show:top",
+		},
+		{
+			name:    "unknown style",
+			config:  SwapConfig{Style: "sideways"},
+			wantErr: true,
+		},
+		{
+			name:    "style with modifiers",
+			config:  SwapConfig{Style: SwapInnerHTML.Transition(true)},
+			wantErr: true,
+		},
+		{
+			name:    "negative settle",
+			config:  SwapConfig{Style: SwapInnerHTML, Settle: -time.Second},
+			wantErr: true,
+		},
+		{
+			name:    "scroll and show",
+			config:  SwapConfig{Style: SwapInnerHTML, Scroll: Top, Show: Top},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		result, err := tc.config.Build()
+		if (err != nil) != tc.wantErr {
+			t.Errorf("%s: got error %v, want error: %v", tc.name, err, tc.wantErr)
+		}
+		if string(result) != tc.result {
+			t.Errorf(`%s: got: "%v", want: "%v"`, tc.name, result, tc.result)
+		}
+	}
+}
+
+func TestSwapStrategy_String(t *testing.T) {
+	s := SwapInnerHTML.Transition(true).Scroll(Bottom)
+
+	if got, want := fmt.Sprintf("%s", s), "innerHTML transition:true scroll:bottom"; got != want {
+		t.Errorf(`got: "%v", want: "%v"`, got, want)
+	}
+	if got := fmt.Sprintf("%v", s); got != s.swapString() {
+		t.Errorf(`got: "%v", want: "%v"`, got, s.swapString())
+	}
+}
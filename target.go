@@ -0,0 +1,61 @@
+package htmx
+
+// TargetThis returns the extended CSS selector for the element that triggered the request.
+//
+// Use it with [htmx.Response.Retarget].
+//
+// For more info, see https://htmx.org/attributes/hx-target/
+func TargetThis() string {
+	return "this"
+}
+
+// TargetClosest returns the extended CSS selector for the closest ancestor element
+// (or the element itself) that matches the given CSS selector.
+//
+// Use it with [htmx.Response.Retarget].
+//
+// For more info, see https://htmx.org/attributes/hx-target/
+func TargetClosest(cssSelector string) string {
+	return extendedSelector("closest", cssSelector)
+}
+
+// TargetFind returns the extended CSS selector for the first child descendant element
+// that matches the given CSS selector.
+//
+// Use it with [htmx.Response.Retarget].
+//
+// For more info, see https://htmx.org/attributes/hx-target/
+func TargetFind(cssSelector string) string {
+	return extendedSelector("find", cssSelector)
+}
+
+// TargetNext returns the extended CSS selector for the next element in the DOM
+// matching the given CSS selector.
+//
+// If the selector is empty, the next sibling element is targeted instead.
+//
+// Use it with [htmx.Response.Retarget].
+//
+// For more info, see https://htmx.org/attributes/hx-target/
+func TargetNext(cssSelector string) string {
+	return extendedSelector("next", cssSelector)
+}
+
+// TargetPrevious returns the extended CSS selector for the previous element in the DOM
+// matching the given CSS selector.
+//
+// If the selector is empty, the previous sibling element is targeted instead.
+//
+// Use it with [htmx.Response.Retarget].
+//
+// For more info, see https://htmx.org/attributes/hx-target/
+func TargetPrevious(cssSelector string) string {
+	return extendedSelector("previous", cssSelector)
+}
+
+func extendedSelector(keyword string, cssSelector string) string {
+	if cssSelector == "" {
+		return keyword
+	}
+	return keyword + " " + cssSelector
+}
@@ -0,0 +1,34 @@
+package htmx
+
+import "testing"
+
+func TestTargetSelectors(t *testing.T) {
+	testCases := []struct {
+		name   string
+		result string
+		want   string
+	}{
+		{name: "this", result: TargetThis(), want: "this"},
+		{name: "closest", result: TargetClosest("tr"), want: "closest tr"},
+		{name: "find", result: TargetFind(".error"), want: "find .error"},
+		{name: "next with selector", result: TargetNext("div.card"), want: "next div.card"},
+		{name: "next sibling", result: TargetNext(""), want: "next"},
+		{name: "previous with selector", result: TargetPrevious("li"), want: "previous li"},
+		{name: "previous sibling", result: TargetPrevious(""), want: "previous"},
+	}
+
+	for _, tc := range testCases {
+		if tc.result != tc.want {
+			t.Errorf(`%s: got: "%v", want: "%v"`, tc.name, tc.result, tc.want)
+		}
+	}
+
+	res := NewResponse().Retarget(TargetClosest("tr"))
+	headers, err := res.Headers()
+	if err != nil {
+		t.Errorf("an error occurred writing a response: %v", err)
+	}
+	if got := headers[HeaderRetarget]; got != "closest tr" {
+		t.Errorf("wrong value for header %q. got=%q, want=%q", HeaderRetarget, got, "closest tr")
+	}
+}
@@ -0,0 +1,98 @@
+package htmx
+
+import (
+	"net/http"
+)
+
+// wrappedWriter applies the headers of a response on the first write.
+type wrappedWriter struct {
+	http.ResponseWriter
+	r Response
+
+	// Whether the headers of the response were applied, and the error if they failed to
+	applied bool
+	err     error
+
+	// Whether the status code was written to the underlying writer
+	wroteHeader bool
+}
+
+// Wrap returns a response writer that applies the defined HTMX headers and status code
+// on the first call to its Write, WriteHeader or Flush method, for code that writes
+// the body directly, e.g. a framework renderer.
+//
+// If the response has a status code set, it takes precedence over the status code
+// passed to WriteHeader. The status code is written to the underlying writer only once,
+// so later WriteHeader calls, e.g. from writing another response to the wrapped writer
+// in a middleware stack, are ignored.
+//
+// If the headers fail to apply, e.g. because a trigger can't be marshalled, every Write
+// returns the error and the body is not written; the error is also passed to the
+// [htmx.Response.OnError] callbacks. The [htmx.Response.OnWritten] callbacks run
+// once the headers are applied.
+//
+// The wrapped writer supports [http.ResponseController] and implements [http.Flusher],
+// flushing only if the underlying writer supports it.
+func (r Response) Wrap(w http.ResponseWriter) http.ResponseWriter {
+	return &wrappedWriter{ResponseWriter: w, r: r}
+}
+
+// apply writes the headers of the response if they haven't been written yet.
+func (ww *wrappedWriter) apply() error {
+	if ww.applied {
+		return ww.err
+	}
+	ww.applied = true
+
+	if err := ww.r.write(ww.ResponseWriter); err != nil {
+		ww.err = ww.r.failed(err)
+		return ww.err
+	}
+	if ww.r.statusCode != 0 {
+		ww.wroteHeader = true
+	}
+
+	ww.r.written()
+	return nil
+}
+
+func (ww *wrappedWriter) WriteHeader(statusCode int) {
+	// The error is returned by the next Write
+	_ = ww.apply()
+
+	if ww.wroteHeader {
+		return
+	}
+	ww.wroteHeader = true
+	ww.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (ww *wrappedWriter) Write(b []byte) (int, error) {
+	if err := ww.apply(); err != nil {
+		return 0, err
+	}
+	ww.wroteHeader = true
+	return ww.ResponseWriter.Write(b)
+}
+
+func (ww *wrappedWriter) Flush() {
+	if err := ww.apply(); err != nil {
+		return
+	}
+	if flusher, ok := ww.ResponseWriter.(http.Flusher); ok {
+		ww.wroteHeader = true
+		flusher.Flush()
+	}
+}
+
+// wroteHeader returns true if w is a writer from [htmx.Response.Wrap]
+// that already wrote a status code.
+func wroteHeader(w http.ResponseWriter) bool {
+	ww, ok := w.(*wrappedWriter)
+	return ok && ww.wroteHeader
+}
+
+// Unwrap returns the underlying response writer, for [http.ResponseController].
+func (ww *wrappedWriter) Unwrap() http.ResponseWriter {
+	return ww.ResponseWriter
+}
@@ -0,0 +1,150 @@
+package htmx
+
+import (
+	"io"
+	"net/http"
+	"testing"
+)
+
+func TestWrap(t *testing.T) {
+	w := newMockResponseWriter()
+
+	written := 0
+	ww := NewResponse().
+		Retarget("#contacts").
+		AddTrigger(Trigger("contactAdded")).
+		OnWritten(func() { written++ }).
+		Wrap(w)
+
+	if len(w.Header()) != 0 {
+		t.Errorf("headers should not be applied before the first write, got %v", w.Header())
+	}
+
+	if _, err := io.WriteString(ww, "<li>Joe</li>"); err != nil {
+		t.Errorf("an error occurred writing a body: %v", err)
+	}
+	if _, err := io.WriteString(ww, "<li>Ann</li>"); err != nil {
+		t.Errorf("an error occurred writing a body: %v", err)
+	}
+
+	expectedHeaders := map[string]string{
+		HeaderRetarget: "#contacts",
+		HeaderTrigger:  "contactAdded",
+	}
+	for k, v := range expectedHeaders {
+		if got := w.Header().Get(k); got != v {
+			t.Errorf("wrong value for header %q. got=%q, want=%q", k, got, v)
+		}
+	}
+
+	if got := string(w.body); got != "<li>Joe</li><li>Ann</li>" {
+		t.Errorf(`got: "%v", want: "%v"`, got, "<li>Joe</li><li>Ann</li>")
+	}
+	if written != 1 {
+		t.Errorf("wrong number of OnWritten calls. got=%d, want=%d", written, 1)
+	}
+}
+
+func TestWrap_WriteHeader(t *testing.T) {
+	testCases := []struct {
+		name       string
+		res        Response
+		statusCode int
+	}{
+		{
+			name:       "status code from WriteHeader",
+			res:        NewResponse().Retarget("#errors"),
+			statusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "status code from the response",
+			res:        NewResponse().Retarget("#errors").StopPolling(),
+			statusCode: StatusStopPolling,
+		},
+	}
+
+	for _, tc := range testCases {
+		w := newMockResponseWriter()
+		tc.res.Wrap(w).WriteHeader(http.StatusUnprocessableEntity)
+
+		if w.statusCode != tc.statusCode {
+			t.Errorf("%s: wrong status code. want=%v, got=%v", tc.name, tc.statusCode, w.statusCode)
+		}
+		if got := w.Header().Get(HeaderRetarget); got != "#errors" {
+			t.Errorf("%s: wrong value for header %q. got=%q, want=%q", tc.name, HeaderRetarget, got, "#errors")
+		}
+	}
+}
+
+func TestWrap_Error(t *testing.T) {
+	var errs []error
+
+	w := newMockResponseWriter()
+	ww := NewResponse().
+		AddTrigger(TriggerObject("bad", make(chan int))).
+		OnError(func(err error) { errs = append(errs, err) }).
+		Wrap(w)
+
+	if _, err := io.WriteString(ww, "<li>Joe</li>"); err == nil {
+		t.Errorf("expected an error")
+	}
+	if len(errs) != 1 {
+		t.Errorf("wrong number of OnError calls. got=%d, want=%d", len(errs), 1)
+	}
+	if len(w.body) != 0 {
+		t.Errorf("body should not be written when the headers fail, got %q", w.body)
+	}
+}
+
+func TestWrap_Flush(t *testing.T) {
+	w := &mockFlusher{mockResponseWriter: newMockResponseWriter()}
+	ww := NewResponse().Retarget("#stream").Wrap(w)
+
+	http.NewResponseController(ww).Flush()
+
+	if w.flushes != 1 {
+		t.Errorf("wrong number of flushes. got=%v, want=%v", w.flushes, 1)
+	}
+	if got := w.Header().Get(HeaderRetarget); got != "#stream" {
+		t.Errorf("wrong value for header %q. got=%q, want=%q", HeaderRetarget, got, "#stream")
+	}
+}
+
+func TestWrap_WriteHeaderError(t *testing.T) {
+	w := newMockResponseWriter()
+	ww := NewResponse().
+		Retarget("#errors").
+		AddTrigger(TriggerObject("bad", make(chan int))).
+		Wrap(w)
+
+	ww.WriteHeader(http.StatusOK)
+
+	for i := 0; i < 2; i++ {
+		if _, err := io.WriteString(ww, "<li>Joe</li>"); err == nil {
+			t.Errorf("write %d: expected the error from applying the headers", i)
+		}
+	}
+	if len(w.body) != 0 {
+		t.Errorf("body should not be written without the HTMX headers, got %q", w.body)
+	}
+}
+
+func TestWrap_StatusCodeOnce(t *testing.T) {
+	w := &countingHeaderWriter{mockResponseWriter: newMockResponseWriter()}
+	ww := NewResponse().Retarget("#main").Wrap(w)
+
+	res := NewResponse().StatusCode(http.StatusAccepted)
+	for i := 0; i < 2; i++ {
+		if err := res.Write(ww); err != nil {
+			t.Errorf("an error occurred writing a response: %v", err)
+		}
+	}
+	ww.WriteHeader(http.StatusOK)
+
+	if w.writeHeaderCalls != 1 {
+		t.Errorf("wrong number of WriteHeader calls. got=%v, want=%v", w.writeHeaderCalls, 1)
+	}
+	if w.statusCode != http.StatusAccepted {
+		t.Errorf("wrong status code. want=%v, got=%v", http.StatusAccepted, w.statusCode)
+	}
+}